@@ -1,18 +1,19 @@
 package cmd
 
 import (
-	"fmt"
-	"net/url"
+	"context"
 	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
 )
 
 var (
 	articleTitle    string
 	articleIntro    string
 	articleSections string
+
+	articleIntroFile string
 )
 
 var articleCmd = &cobra.Command{
@@ -24,7 +25,9 @@ var articleV3Cmd = &cobra.Command{
 	Use:   "write",
 	Short: "Generate a long-form SEO article (AI Article Writer v3)",
 	Example: `  writesonic article write --title "10 AI Tools in 2025" --intro "AI is transforming..." --sections "Tools,Use cases,Future"
-  writesonic article write --title "Healthy Eating" --intro "Good nutrition is key" --sections "Benefits,Tips,Recipes" --copies 1`,
+  writesonic article write --title "Healthy Eating" --intro "Good nutrition is key" --sections "Benefits,Tips,Recipes" --copies 1
+  writesonic article write --title "Remote Work" --intro-file intro.md --sections "Tools,Culture"
+  writesonic article write --title "Remote Work" --intro-file intro.md --sections "Tools,Culture" --site hugo --keyword remote --meta`,
 	RunE: runArticleV3,
 }
 
@@ -32,7 +35,8 @@ var articleInstantCmd = &cobra.Command{
 	Use:   "instant",
 	Short: "Generate a 1500-word article instantly",
 	Example: `  writesonic article instant --title "How to Learn Python in 2025"
-  writesonic article instant --title "Best Coffee Shops in Paris" --engine premium`,
+  writesonic article instant --title "Best Coffee Shops in Paris" --engine premium
+  writesonic article instant --title "Best Coffee Shops in Paris" --site jekyll --keyword paris,coffee`,
 	RunE: runArticleInstant,
 }
 
@@ -42,24 +46,32 @@ var (
 
 func init() {
 	articleV3Cmd.Flags().StringVar(&articleTitle, "title", "", "Article title (required)")
-	articleV3Cmd.Flags().StringVar(&articleIntro, "intro", "", "Article introduction (required)")
+	articleV3Cmd.Flags().StringVar(&articleIntro, "intro", "", "Article introduction (required; - for stdin)")
+	articleV3Cmd.Flags().StringVar(&articleIntroFile, "intro-file", "", "Read the introduction from a file")
 	articleV3Cmd.Flags().StringVar(&articleSections, "sections", "", "Comma-separated section titles (required)")
 	articleV3Cmd.MarkFlagRequired("title")
-	articleV3Cmd.MarkFlagRequired("intro")
 	articleV3Cmd.MarkFlagRequired("sections")
 
 	articleInstantCmd.Flags().StringVar(&instantTitle, "title", "", "Article title (required)")
 	articleInstantCmd.MarkFlagRequired("title")
 
+	addSiteFlags(articleV3Cmd)
+	addSiteFlags(articleInstantCmd)
+
+	addPickFlag(articleV3Cmd, articleInstantCmd)
+	addOutputFlags(articleV3Cmd, articleInstantCmd)
 	articleCmd.AddCommand(articleV3Cmd, articleInstantCmd)
 	rootCmd.AddCommand(articleCmd)
 }
 
 func runArticleV3(cmd *cobra.Command, args []string) error {
-	params := url.Values{}
-	params.Set("engine", engineFlag)
-	params.Set("language", langFlag)
-	params.Set("num_copies", fmt.Sprintf("%d", copiesFlag))
+	if err := checkSiteFlags(); err != nil {
+		return err
+	}
+	intro, err := textInput{flag: "intro", value: articleIntro, file: articleIntroFile}.require()
+	if err != nil {
+		return err
+	}
 
 	// Parse comma-separated sections into slice
 	rawSections := strings.Split(articleSections, ",")
@@ -71,52 +83,31 @@ func runArticleV3(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	body := map[string]interface{}{
-		"article_title":    articleTitle,
-		"article_intro":    articleIntro,
-		"article_sections": sections,
-	}
+	return printArticle(cmd.Context(), articleTitle, call(client.Article, writesonic.ArticleRequest{
+		Title:    articleTitle,
+		Intro:    intro,
+		Sections: sections,
+	}))
+}
 
-	results, err := client.PostResults("/ai-article-writer-v3", params, body)
-	if err != nil {
+func runArticleInstant(cmd *cobra.Command, args []string) error {
+	if err := checkSiteFlags(); err != nil {
 		return err
 	}
-
-	if output.IsJSON(jsonFlag, prettyFlag) {
-		return output.PrintJSON(results, prettyFlag)
-	}
-
-	texts := make([]string, len(results))
-	for i, r := range results {
-		texts[i] = r.Text
-	}
-	output.PrintText(texts)
-	return nil
+	return printArticle(cmd.Context(), instantTitle, call(client.InstantArticle, writesonic.InstantArticleRequest{
+		Title: instantTitle,
+	}))
 }
 
-func runArticleInstant(cmd *cobra.Command, args []string) error {
-	params := url.Values{}
-	params.Set("engine", engineFlag)
-	params.Set("language", langFlag)
-	params.Set("num_copies", fmt.Sprintf("%d", copiesFlag))
-
-	body := map[string]interface{}{
-		"article_title": instantTitle,
+// printArticle makes the call, then exports the articles with --site or
+// prints them like any other result.
+func printArticle(ctx context.Context, title string, c textCall) error {
+	if siteFlag == "" {
+		return printCall(ctx, c)
 	}
-
-	results, err := client.PostResults("/instant-article-writer", params, body)
+	results, err := c(ctx, copiesFlag)
 	if err != nil {
 		return err
 	}
-
-	if output.IsJSON(jsonFlag, prettyFlag) {
-		return output.PrintJSON(results, prettyFlag)
-	}
-
-	texts := make([]string, len(results))
-	for i, r := range results {
-		texts[i] = r.Text
-	}
-	output.PrintText(texts)
-	return nil
+	return exportSite(ctx, title, results)
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"github.com/the20100/writesonic-cli/internal/config"
@@ -13,10 +14,23 @@ var authCmd = &cobra.Command{
 }
 
 var authSetKeyCmd = &cobra.Command{
-	Use:   "set-key <api-key>",
+	Use:   "set-key",
 	Short: "Save your Writesonic API key",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runAuthSetKey,
+	Long: `Save your Writesonic API key for the selected profile.
+
+The key is read from a hidden prompt, or from stdin when it is piped, so it
+never appears in shell history or the process list. Where it is stored depends
+on the credential_store setting (or --store):
+
+  config          plaintext in config.json (0600), the default
+  keyring         OS keychain, Secret Service (D-Bus) or Windows Credential Manager
+  encrypted-file  credentials.enc, encrypted with a passphrase
+                  (prompted, or taken from WRITESONIC_PASSPHRASE)`,
+	Example: `  writesonic auth set-key
+  writesonic auth set-key --store keyring
+  pass show writesonic | writesonic auth set-key --profile acme`,
+	Args: cobra.NoArgs,
+	RunE: runAuthSetKey,
 }
 
 var authStatusCmd = &cobra.Command{
@@ -41,58 +55,150 @@ var (
 	configEngine   string
 	configLanguage string
 	configCopies   int
+
+	setKeyStore string
 )
 
 func init() {
 	authConfigCmd.Flags().StringVar(&configEngine, "engine", "", "Default engine (economy, average, good, premium)")
 	authConfigCmd.Flags().StringVar(&configLanguage, "language", "", "Default language code (e.g. en, fr, de)")
 	authConfigCmd.Flags().IntVar(&configCopies, "copies", 0, "Default number of copies (1-5)")
+	authSetKeyCmd.Flags().StringVar(&setKeyStore, "store", "", "Credential store to use from now on: config, keyring or encrypted-file")
 
 	authCmd.AddCommand(authSetKeyCmd, authStatusCmd, authLogoutCmd, authConfigCmd)
 	rootCmd.AddCommand(authCmd)
 }
 
-func runAuthSetKey(cmd *cobra.Command, args []string) error {
-	key := args[0]
+// loadProfileTarget loads the config file and returns the profile selected
+// by --profile / WRITESONIC_PROFILE / active_profile for modification.
+func loadProfileTarget() (*config.Config, string, *config.Profile, error) {
+	cfg, err := loadConfigForEdit()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	name, _ := profileName(cfg)
+	p, err := cfg.LookupProfile(name)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return cfg, name, p, nil
+}
 
-	cfg, err := config.Load()
+// saveAPIKey stores key for the named profile in cfg's credential store and
+// saves cfg, dropping any plaintext copy when the store is not the config
+// file. It returns a description of where the key went.
+func saveAPIKey(cfg *config.Config, name, key string) (string, error) {
+	store, err := credentialStore(cfg)
+	if err != nil {
+		return "", err
+	}
+	p, err := cfg.LookupProfile(name)
 	if err != nil {
-		cfg = &config.Config{}
+		return "", err
+	}
+	if store.Name() == config.StoreConfig {
+		p.APIKey = key
+	} else {
+		if err := store.Set(name, key); err != nil {
+			return "", err
+		}
+		p.APIKey = ""
 	}
-	cfg.APIKey = key
 	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("save config: %w", err)
+		return "", fmt.Errorf("save config: %w", err)
 	}
+	return storeLocation(store), nil
+}
 
+// deleteAPIKey removes the named profile's key from the credential store and
+// from the config file. cfg is not saved.
+func deleteAPIKey(cfg *config.Config, name string) error {
+	if p, err := cfg.LookupProfile(name); err == nil {
+		p.APIKey = ""
+	}
+	store, err := credentialStore(cfg)
+	if err != nil || store.Name() == config.StoreConfig {
+		return err
+	}
+	return store.Delete(name)
+}
+
+func storeLocation(store config.CredentialStore) string {
+	switch store.Name() {
+	case config.StoreKeyring:
+		return "the OS keyring"
+	case config.StoreEncryptedFile:
+		dir, _ := config.Dir()
+		return filepath.Join(dir, "credentials.enc")
+	}
 	path, _ := config.ConfigPath()
-	fmt.Printf("API key saved to %s\n", path)
+	return path
+}
+
+func runAuthSetKey(cmd *cobra.Command, args []string) error {
+	key, err := readAPIKeyInput()
+	if err != nil {
+		return err
+	}
+
+	cfg, name, _, err := loadProfileTarget()
+	if err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("store") {
+		if _, err := cfg.CredentialBackendNamed(setKeyStore, nil); err != nil {
+			return err
+		}
+		if setKeyStore == config.StoreConfig {
+			setKeyStore = ""
+		}
+		cfg.CredentialStore = setKeyStore
+	}
+	where, err := saveAPIKey(cfg, name, key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("API key for profile %q saved to %s\n", name, where)
 	fmt.Println("You can now run: writesonic blog-ideas --topic \"AI in 2025\"")
 	return nil
 }
 
 func runAuthStatus(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
+	layers, err := loadConfigLayers()
 	if err != nil {
-		return fmt.Errorf("load config: %w", err)
+		return err
 	}
+	cfg, name, nameSource := layers.effective, layers.profile, layers.profileSource
 
 	path, _ := config.ConfigPath()
 	fmt.Printf("Config file:      %s\n", path)
+	if layers.project != nil {
+		fmt.Printf("Project config:   %s\n", layers.project.Path)
+	}
+	fmt.Printf("Profile:          %s (%s)\n", name, nameSource)
+	store := cfg.CredentialStore
+	if store == "" {
+		store = config.StoreConfig
+	}
+	fmt.Printf("Credential store: %s\n", store)
 
-	key := resolveAPIKey()
+	key, source, err := resolveAPIKeyFrom(cfg, name)
+	if err != nil {
+		return err
+	}
 	if key == "" {
 		fmt.Println("API key:          not set")
-		fmt.Println("\nRun: writesonic auth set-key <your-key>")
+		if config.IsDefaultProfile(name) {
+			fmt.Println("\nRun: writesonic auth set-key")
+		} else {
+			fmt.Printf("\nRun: writesonic auth set-key --profile %s\n", name)
+		}
 		fmt.Println("Or:  export WRITESONIC_API_KEY=<your-key>")
 		return nil
 	}
 
-	masked := maskKey(key)
-	source := "environment variable"
-	if cfg.APIKey != "" && key == cfg.APIKey {
-		source = "config file"
-	}
-	fmt.Printf("API key:          %s (%s)\n", masked, source)
+	fmt.Printf("API key:          %s (%s)\n", maskKey(key), source)
 
 	engine := cfg.DefaultEngine
 	if engine == "" {
@@ -110,37 +216,41 @@ func runAuthStatus(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Printf("Default copies:   %d\n", copies)
 	}
+	if cfg.BaseURL != "" {
+		fmt.Printf("Base URL:         %s\n", cfg.BaseURL)
+	}
 	return nil
 }
 
 func runAuthLogout(cmd *cobra.Command, args []string) error {
-	if err := config.Clear(); err != nil {
-		return fmt.Errorf("clear config: %w", err)
+	cfg, name, _, err := loadProfileTarget()
+	if err != nil {
+		return err
+	}
+	if err := deleteAPIKey(cfg, name); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
 	}
-	fmt.Println("API key removed. Set a new key with: writesonic auth set-key <key>")
+	fmt.Printf("API key removed from profile %q. Set a new key with: writesonic auth set-key\n", name)
 	return nil
 }
 
 func runAuthConfig(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
+	cfg, name, _, err := loadProfileTarget()
 	if err != nil {
-		cfg = &config.Config{}
+		return err
 	}
 
-	changed := false
-	if configEngine != "" {
-		cfg.DefaultEngine = configEngine
-		changed = true
-	}
-	if configLanguage != "" {
-		cfg.DefaultLanguage = configLanguage
-		changed = true
-	}
-	if configCopies > 0 {
-		cfg.DefaultCopies = configCopies
-		changed = true
+	changed, err := setProfileDefaults(cmd, cfg, name, map[string]string{
+		"engine":   "default_engine",
+		"language": "default_language",
+		"copies":   "default_copies",
+	})
+	if err != nil {
+		return err
 	}
-
 	if !changed {
 		fmt.Println("No changes. Use --engine, --language, or --copies flags.")
 		fmt.Println("Example: writesonic auth config --engine premium --language fr --copies 3")
@@ -150,10 +260,31 @@ func runAuthConfig(cmd *cobra.Command, args []string) error {
 	if err := cfg.Save(); err != nil {
 		return fmt.Errorf("save config: %w", err)
 	}
-	fmt.Println("Defaults updated.")
+	fmt.Printf("Defaults updated for profile %q.\n", name)
 	return nil
 }
 
+// setProfileDefaults validates and applies the changed flags, mapped to
+// their setting names, to the named profile in cfg.
+func setProfileDefaults(cmd *cobra.Command, cfg *config.Config, profile string, flagKeys map[string]string) (bool, error) {
+	prefix := ""
+	if !config.IsDefaultProfile(profile) {
+		prefix = "profiles." + profile + "."
+	}
+	changed := false
+	for flag, key := range flagKeys {
+		f := cmd.Flags().Lookup(flag)
+		if f == nil || !f.Changed {
+			continue
+		}
+		if err := cfg.Set(prefix+key, f.Value.String()); err != nil {
+			return false, fmt.Errorf("--%s: %w", flag, err)
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
 func maskKey(key string) string {
 	if len(key) <= 8 {
 		return "****"
@@ -0,0 +1,577 @@
+package cmd
+
+// batch.go runs many generation requests from a CSV or JSONL file and writes
+// one JSONL result line per row. Rows already marked "ok" in the output file
+// are skipped, so an interrupted run can be resumed without re-billing.
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/internal/api"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+// batchSpec describes how a CLI command maps onto an API request. Fields are
+// keyed by the command's flag name and map to the JSON body key.
+type batchSpec struct {
+	path     string
+	fields   map[string]string
+	required []string
+	lists    map[string]bool // fields sent as a JSON array (comma-separated in CSV)
+	landing  bool            // response decodes as []LandingPage
+}
+
+var batchSpecs = map[string]batchSpec{
+	"blog-ideas": {
+		path:     "/blog-ideas",
+		fields:   map[string]string{"topic": "topic", "keyword": "primary_keyword"},
+		required: []string{"topic"},
+	},
+	"article write": {
+		path:     "/ai-article-writer-v3",
+		fields:   map[string]string{"title": "article_title", "intro": "article_intro", "sections": "article_sections"},
+		required: []string{"title", "intro", "sections"},
+		lists:    map[string]bool{"sections": true},
+	},
+	"article instant": {
+		path:     "/instant-article-writer",
+		fields:   map[string]string{"title": "article_title"},
+		required: []string{"title"},
+	},
+	"landing page": {
+		path: "/landing-pages",
+		fields: map[string]string{
+			"name": "product_name", "desc": "product_description",
+			"f1": "feature_1", "f2": "feature_2", "f3": "feature_3",
+		},
+		required: []string{"name", "desc", "f1", "f2", "f3"},
+		landing:  true,
+	},
+	"landing headline": {
+		path:     "/landing-page-headlines",
+		fields:   map[string]string{"name": "product_name", "desc": "product_description"},
+		required: []string{"name", "desc"},
+	},
+	"copy pas": {
+		path:     "/pas",
+		fields:   map[string]string{"name": "product_name", "desc": "product_description"},
+		required: []string{"name", "desc"},
+	},
+	"copy aida": {
+		path:     "/aida",
+		fields:   map[string]string{"name": "product_name", "desc": "product_description"},
+		required: []string{"name", "desc"},
+	},
+	"copy cta": {
+		path:     "/call-to-action",
+		fields:   map[string]string{"name": "product_name"},
+		required: []string{"name"},
+	},
+	"copy bullets": {
+		path:     "/bulletpoint-answers",
+		fields:   map[string]string{"question": "question"},
+		required: []string{"question"},
+	},
+	"rewrite rephrase": {
+		path:     "/content-rephrase",
+		fields:   map[string]string{"content": "content_to_rephrase", "tone": "tone_of_voice"},
+		required: []string{"content"},
+	},
+	"rewrite shorten": {
+		path:     "/content-shorten",
+		fields:   map[string]string{"content": "content_to_shorten", "tone": "tone_of_voice"},
+		required: []string{"content"},
+	},
+	"rewrite tone": {
+		path:     "/tone-changer",
+		fields:   map[string]string{"content": "content_to_change", "tone": "tone"},
+		required: []string{"content", "tone"},
+	},
+	"rewrite keywords": {
+		path:     "/rewrite-with-keywords",
+		fields:   map[string]string{"content": "content", "keywords": "keywords"},
+		required: []string{"content", "keywords"},
+	},
+	"write paragraph": {
+		path:     "/paragraph-writer",
+		fields:   map[string]string{"topic": "topic", "instructions": "instructions"},
+		required: []string{"topic"},
+	},
+	"write meta": {
+		path:     "/meta-blog",
+		fields:   map[string]string{"title": "blog_title", "desc": "blog_description"},
+		required: []string{"title", "desc"},
+	},
+	"write conclusion": {
+		path:     "/conclusion-writer",
+		fields:   map[string]string{"topic": "topic"},
+		required: []string{"topic"},
+	},
+}
+
+// batchRow is one input record: an ID, the command to run and its fields.
+type batchRow struct {
+	ID      string
+	Command string
+	Fields  map[string]interface{}
+}
+
+// batchRecord is one line of the JSONL output.
+type batchRecord struct {
+	ID         string      `json:"id"`
+	Command    string      `json:"command"`
+	Status     string      `json:"status"`
+	Results    interface{} `json:"results,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	ErrorClass string      `json:"error_class,omitempty"`
+}
+
+var (
+	batchOut         string
+	batchInputFormat string
+	batchRestart     bool
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch <input-file>",
+	Short: "Run many generation requests from a CSV or JSONL file",
+	Long: `Run generation requests in bulk. Each input row names a command and its fields,
+using the same names as the command's flags:
+
+  CSV:   id,command,name,desc
+         p1,copy cta,Acme,
+         p2,copy pas,FitTrack,Fitness tracking app
+
+  JSONL: {"id":"p1","command":"copy cta","name":"Acme"}
+         {"id":"a1","command":"article write","title":"...","intro":"...","sections":["A","B"]}
+
+Optional per-row columns "engine", "language" and "copies" override the global flags.
+If "id" is missing the 1-based row number is used.
+
+Results are written as JSONL, one line per row with status "ok" or "error".
+When --out points at an existing file, rows already recorded as "ok" are skipped,
+so a crashed or interrupted run can be resumed without paying for them again.
+Failed rows are retried on resume and appended again; the last line for an ID wins.
+Use "-" as the input file to read from stdin.
+
+Rows run --concurrency at a time (subject to --rate-limit); results are
+written in input order and progress is reported on stderr. On an interrupt or
+an early stop, rows that already finished are still written (possibly after a
+gap), so they are never billed twice.`,
+	Example: `  writesonic batch products.csv --out results.jsonl
+  writesonic batch jobs.jsonl --out results.jsonl --engine economy
+  writesonic batch catalog.csv --out results.jsonl --concurrency 8 --rate-limit premium=30,default=120
+  cat jobs.jsonl | writesonic batch - --input-format jsonl`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBatch,
+}
+
+func init() {
+	batchCmd.Flags().StringVar(&batchOut, "out", "", "JSONL results file (default stdout; enables resume)")
+	batchCmd.Flags().StringVar(&batchInputFormat, "input-format", "", "Input format: csv or jsonl (default from file extension)")
+	batchCmd.Flags().BoolVar(&batchRestart, "restart", false, "Ignore previous results in --out and start over")
+	rootCmd.AddCommand(batchCmd)
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	rows, err := readBatchRows(args[0], batchInputFormat)
+	if err != nil {
+		return err
+	}
+
+	done := map[string]bool{}
+	w := io.Writer(os.Stdout)
+	if batchOut != "" {
+		if !batchRestart {
+			if done, err = completedBatchRows(batchOut); err != nil {
+				return err
+			}
+		}
+		flags := os.O_CREATE | os.O_RDWR | os.O_APPEND
+		if batchRestart {
+			flags |= os.O_TRUNC
+		}
+		f, err := os.OpenFile(batchOut, flags, 0644)
+		if err != nil {
+			return fmt.Errorf("open output: %w", err)
+		}
+		defer f.Close()
+		if err := terminateLastLine(f); err != nil {
+			return err
+		}
+		w = f
+	}
+	enc := json.NewEncoder(w)
+
+	var todo []batchRow
+	for _, row := range rows {
+		if !done[row.ID] {
+			todo = append(todo, row)
+		}
+	}
+	skipped := len(rows) - len(todo)
+
+	jobs := make([]api.Job, len(todo))
+	for i, row := range todo {
+		row := row
+		engine := fieldString(row.Fields["engine"])
+		if engine == "" {
+			engine = engineFlag
+		}
+		jobs[i] = api.Job{
+			Key: engine,
+			Do: func(ctx context.Context) (interface{}, error) {
+				rec := runBatchRow(ctx, row)
+				if rec.Status != "ok" && ctx.Err() != nil {
+					// Cut short by an interrupt or an early stop: nothing was
+					// billed, so the row is left for the next run.
+					return nil, ctx.Err()
+				}
+				return rec, nil
+			},
+		}
+	}
+
+	var ok, failed int
+	err = newScheduler().Run(cmd.Context(), jobs, func(r api.JobResult) error {
+		if r.Err != nil {
+			return nil // cancelled before it finished; resumed next run
+		}
+		// Finished rows are always written, even after an interrupt, so a
+		// billed result is never requested again.
+		rec := r.Value.(batchRecord)
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("write result: %w", err)
+		}
+		if rec.Status == "ok" {
+			ok++
+			return nil
+		}
+		failed++
+		fmt.Fprintf(os.Stderr, "row %s: %s\n", rec.ID, rec.Error)
+
+		// Stop early when every remaining row would fail the same way;
+		// completed rows are preserved for the next run.
+		if rec.ErrorClass == writesonic.ClassAuth.String() || rec.ErrorClass == writesonic.ClassQuota.String() {
+			return fmt.Errorf("stopping batch after %s error on row %s (rerun to resume): %s",
+				rec.ErrorClass, rec.ID, rec.Error)
+		}
+		return nil
+	})
+
+	fmt.Fprintf(os.Stderr, "batch: %d ok, %d failed, %d skipped\n", ok, failed, skipped)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", failed, len(todo))
+	}
+	return nil
+}
+
+// runBatchRow executes a single row and returns its output record.
+func runBatchRow(ctx context.Context, row batchRow) batchRecord {
+	rec := batchRecord{ID: row.ID, Command: row.Command}
+	fail := func(err error) batchRecord {
+		err = explainColumnError(row.Command, err)
+		rec.Status = "error"
+		rec.Error = err.Error()
+		if class := writesonic.Classify(err); class != writesonic.ClassUnknown {
+			rec.ErrorClass = class.String()
+		}
+		return rec
+	}
+
+	results, err := callCommand(ctx, row.Command, row.Fields)
+	if err != nil {
+		return fail(err)
+	}
+	rec.Results = results
+	rec.Status = "ok"
+	return rec
+}
+
+// callCommand runs one request for a batch-capable command, with fields keyed
+// by the command's flag names. The results are []writesonic.LandingPage for
+// "landing page" and []writesonic.Result otherwise.
+func callCommand(ctx context.Context, command string, fields map[string]interface{}) (interface{}, error) {
+	spec, ok := batchSpecs[command]
+	if !ok {
+		return nil, fmt.Errorf("unknown command %q (supported: %s)",
+			command, strings.Join(batchCommandNames(), ", "))
+	}
+	params, body, err := spec.request(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.landing {
+		var req writesonic.LandingPageRequest
+		if err := remarshal(body, &req); err != nil {
+			return nil, err
+		}
+		return client.LandingPage(ctx, req, writesonic.WithParams(params))
+	}
+	return client.Call(ctx, spec.path, body, writesonic.WithParams(params))
+}
+
+// request builds call params and a JSON body from row fields, applying
+// per-row engine/language/copies overrides on top of the global flags.
+func (s batchSpec) request(fields map[string]interface{}) (writesonic.Params, map[string]interface{}, error) {
+	params := writesonic.Params{Engine: engineFlag, Language: langFlag, Copies: copiesFlag}
+	if v := fieldString(fields["engine"]); v != "" {
+		params.Engine = v
+	}
+	if v := fieldString(fields["language"]); v != "" {
+		params.Language = v
+	}
+	if v := fieldString(fields["copies"]); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return params, nil, fmt.Errorf("invalid copies %q", v)
+		}
+		params.Copies = n
+	}
+
+	for _, name := range s.required {
+		if isEmptyField(fields[name]) {
+			return params, nil, fmt.Errorf("missing required field %q", name)
+		}
+	}
+
+	body := map[string]interface{}{}
+	for name, key := range s.fields {
+		v, ok := fields[name]
+		if !ok || isEmptyField(v) {
+			continue
+		}
+		if s.lists[name] {
+			body[key] = fieldList(v)
+		} else {
+			body[key] = fieldString(v)
+		}
+	}
+	return params, body, nil
+}
+
+// remarshal copies a JSON body into a typed request struct.
+func remarshal(body map[string]interface{}, req interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, req)
+}
+
+func fieldString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return strings.TrimSpace(fmt.Sprint(t))
+	}
+}
+
+func fieldList(v interface{}) []string {
+	var raw []string
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			raw = append(raw, fieldString(item))
+		}
+	default:
+		raw = strings.Split(fieldString(v), ",")
+	}
+	out := make([]string, 0, len(raw))
+	for _, s := range raw {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func isEmptyField(v interface{}) bool {
+	if list, ok := v.([]interface{}); ok {
+		return len(list) == 0
+	}
+	return fieldString(v) == ""
+}
+
+// readBatchRows loads rows from path ("-" for stdin) in CSV or JSONL format.
+func readBatchRows(path, format string) ([]batchRow, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("open input: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = "csv"
+		case ".jsonl", ".ndjson", ".json":
+			format = "jsonl"
+		default:
+			return nil, fmt.Errorf("cannot infer input format of %q — use --input-format csv|jsonl", path)
+		}
+	}
+
+	var records []map[string]interface{}
+	var err error
+	switch format {
+	case "csv":
+		records, err = readCSVRecords(r)
+	case "jsonl":
+		records, err = readJSONLRecords(r)
+	default:
+		return nil, fmt.Errorf("unknown input format %q (want csv or jsonl)", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]batchRow, 0, len(records))
+	seen := map[string]bool{}
+	for i, rec := range records {
+		row := batchRow{
+			ID:      fieldString(rec["id"]),
+			Command: strings.Join(strings.Fields(fieldString(rec["command"])), " "),
+			Fields:  rec,
+		}
+		if row.ID == "" {
+			row.ID = strconv.Itoa(i + 1)
+		}
+		if seen[row.ID] {
+			return nil, fmt.Errorf("duplicate row id %q", row.ID)
+		}
+		seen[row.ID] = true
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func readCSVRecords(r io.Reader) ([]map[string]interface{}, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	var records []map[string]interface{}
+	for {
+		line, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read CSV: %w", err)
+		}
+		rec := map[string]interface{}{}
+		for i, v := range line {
+			if i < len(header) {
+				rec[header[i]] = v
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func readJSONLRecords(r io.Reader) ([]map[string]interface{}, error) {
+	var records []map[string]interface{}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			return nil, fmt.Errorf("parse JSONL line %d: %w", n, err)
+		}
+		records = append(records, rec)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read JSONL: %w", err)
+	}
+	return records, nil
+}
+
+// completedBatchRows returns the IDs recorded with status "ok" in an existing
+// results file. A missing file means nothing has completed yet.
+func completedBatchRows(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open previous results: %w", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for sc.Scan() {
+		var rec batchRecord
+		// A torn final line from a crash is ignored; that row simply reruns.
+		if json.Unmarshal(sc.Bytes(), &rec) == nil && rec.Status == "ok" {
+			done[rec.ID] = true
+		}
+	}
+	return done, sc.Err()
+}
+
+// terminateLastLine appends a newline if a previous run crashed mid-line, so
+// new records don't get glued onto the torn one.
+func terminateLastLine(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return fmt.Errorf("check output: %w", err)
+	}
+	if last[0] != '\n' {
+		if _, err := f.Write([]byte("\n")); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+	}
+	return nil
+}
+
+// batchCommandNames returns the supported batch command names, sorted.
+func batchCommandNames() []string {
+	names := make([]string, 0, len(batchSpecs))
+	for name := range batchSpecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -1,11 +1,8 @@
 package cmd
 
 import (
-	"fmt"
-	"net/url"
-
 	"github.com/spf13/cobra"
-	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
 )
 
 var (
@@ -25,35 +22,14 @@ func init() {
 	blogIdeasCmd.Flags().StringVar(&blogTopic, "topic", "", "Topic to generate ideas for (required)")
 	blogIdeasCmd.Flags().StringVar(&blogPrimaryKeyword, "keyword", "", "Primary keyword to focus on")
 	blogIdeasCmd.MarkFlagRequired("topic")
+	addPickFlag(blogIdeasCmd)
+	addOutputFlags(blogIdeasCmd)
 	rootCmd.AddCommand(blogIdeasCmd)
 }
 
 func runBlogIdeas(cmd *cobra.Command, args []string) error {
-	params := url.Values{}
-	params.Set("engine", engineFlag)
-	params.Set("language", langFlag)
-	params.Set("num_copies", fmt.Sprintf("%d", copiesFlag))
-
-	body := map[string]interface{}{
-		"topic": blogTopic,
-	}
-	if blogPrimaryKeyword != "" {
-		body["primary_keyword"] = blogPrimaryKeyword
-	}
-
-	results, err := client.PostResults("/blog-ideas", params, body)
-	if err != nil {
-		return err
-	}
-
-	if output.IsJSON(jsonFlag, prettyFlag) {
-		return output.PrintJSON(results, prettyFlag)
-	}
-
-	texts := make([]string, len(results))
-	for i, r := range results {
-		texts[i] = r.Text
-	}
-	output.PrintText(texts)
-	return nil
+	return printCall(cmd.Context(), call(client.BlogIdeas, writesonic.BlogIdeasRequest{
+		Topic:          blogTopic,
+		PrimaryKeyword: blogPrimaryKeyword,
+	}))
 }
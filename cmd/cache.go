@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/internal/api"
+	"github.com/the20100/writesonic-cli/internal/config"
+	"github.com/the20100/writesonic-cli/internal/output"
+)
+
+var cacheClearExpired bool
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the local response cache",
+	Long: `When enabled with --cache (or "cache": true in the config file), responses are
+stored on disk keyed by a hash of the endpoint, query parameters and request body.
+Repeating an identical request within --cache-ttl is served locally and not billed.`,
+	Annotations: map[string]string{annotationOffline: "true"},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache location, size and entry counts",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheStats,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete cached responses",
+	Example: `  writesonic cache clear
+  writesonic cache clear --expired --cache-ttl 1h`,
+	Args: cobra.NoArgs,
+	RunE: runCacheClear,
+}
+
+func init() {
+	cacheClearCmd.Flags().BoolVar(&cacheClearExpired, "expired", false, "Only delete entries older than --cache-ttl")
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// responseCache returns the on-disk cache using --cache-ttl, or the config
+// value, or 24h.
+func responseCache(cmd *cobra.Command) (*api.Cache, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	ttl := cacheTTLFlag
+	if !cmd.Flags().Changed("cache-ttl") && cfg.CacheTTL != "" {
+		if ttl, err = time.ParseDuration(cfg.CacheTTL); err != nil {
+			return nil, fmt.Errorf("config cache_ttl: %w", err)
+		}
+	}
+	return api.NewCache(filepath.Join(dir, "responses"), ttl), nil
+}
+
+// cacheEnabled reports whether requests should go through the cache.
+func cacheEnabled() bool {
+	if noCacheFlag {
+		return false
+	}
+	return cacheFlag || cfg.Cache
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	c, err := responseCache(cmd)
+	if err != nil {
+		return err
+	}
+	st, err := c.Stats()
+	if err != nil {
+		return err
+	}
+	if output.IsJSON(jsonFlag, prettyFlag) {
+		return output.PrintJSON(st, prettyFlag)
+	}
+	enabled := "no"
+	if cacheEnabled() {
+		enabled = "yes"
+	}
+	output.PrintKeyValue([][]string{
+		{"Directory", st.Dir},
+		{"Enabled", enabled},
+		{"Entries", fmt.Sprintf("%d", st.Entries)},
+		{"Expired", fmt.Sprintf("%d", st.Expired)},
+		{"Size", fmt.Sprintf("%.1f KiB", float64(st.Bytes)/1024)},
+	})
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	c, err := responseCache(cmd)
+	if err != nil {
+		return err
+	}
+	n, err := c.Clear(cacheClearExpired)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d cached responses.\n", n)
+	return nil
+}
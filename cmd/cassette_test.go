@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// execute runs the root command with args and returns what it printed to
+// stdout. Flags are put back to their defaults afterwards.
+func execute(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	out := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- data
+	}()
+
+	rootCmd.SetArgs(args)
+	cmd, err := rootCmd.ExecuteContextC(context.Background())
+	reset := func(f *pflag.Flag) {
+		f.Value.Set(f.DefValue)
+		f.Changed = false
+	}
+	rootCmd.PersistentFlags().VisitAll(reset)
+	if cmd != nil {
+		cmd.Flags().VisitAll(reset)
+	}
+	os.Stdout = stdout
+	w.Close()
+	return string(<-out), err
+}
+
+// TestReplayCommand records a command against a test server, then replays it
+// strictly with the server gone and compares the output to the golden text.
+func TestReplayCommand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	dir := filepath.Join(home, "cassettes")
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"text":"Remote work, ranked"},{"text":"Async by default"}]`))
+	}))
+	args := []string{"blog-ideas", "--topic", "remote work", "--copies", "2", "--no-history"}
+
+	t.Setenv("WRITESONIC_API_KEY", "record-key")
+	recorded, err := execute(t, append(args, "--base-url", srv.URL, "--record", dir)...)
+	srv.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "blog-ideas-*.json"))
+	if len(files) != 1 || calls != 1 {
+		t.Fatalf("cassettes = %v after %d calls, want one blog-ideas-*.json", files, calls)
+	}
+
+	// Piped stdout gets JSON.
+	t.Setenv("WRITESONIC_API_KEY", "replay-key")
+	replayed, err := execute(t, append(args, "--base-url", "http://127.0.0.1:1", "--replay", dir, "--replay-strict")...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"text":"Remote work, ranked"},{"text":"Async by default"}]` + "\n"
+	if replayed != want {
+		t.Errorf("replayed output:\n%s\nwant\n%s", replayed, want)
+	}
+	if recorded != replayed {
+		t.Errorf("recorded output:\n%s\ndiffers from replayed:\n%s", recorded, replayed)
+	}
+
+	_, err = execute(t, "blog-ideas", "--topic", "other", "--no-history",
+		"--base-url", "http://127.0.0.1:1", "--replay", dir, "--replay-strict")
+	if err == nil {
+		t.Error("strict replay of an unrecorded request succeeded")
+	}
+}
@@ -0,0 +1,648 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/internal/config"
+	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+var (
+	configShowResolved bool
+	configListAll      bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change CLI configuration",
+	Long: `get, set, unset, list and edit change the user config file. Keys are validated
+against a schema; nested settings use dotted keys such as rate_limits.premium or
+profiles.acme.default_language.
+
+Settings are resolved in this order, highest first:
+
+  1. command-line flags
+  2. environment variables (WRITESONIC_API_KEY, WRITESONIC_BASE_URL, WRITESONIC_PROFILE)
+  3. the project file: .writesonic.yaml, .writesonic.yml or .writesonic.json,
+     found by walking up from the current directory
+  4. the user config file, with the selected profile's values over the top-level ones
+  5. built-in defaults`,
+	Annotations: map[string]string{annotationOffline: "true"},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the config files in use and what they set",
+	Example: `  writesonic config show
+  writesonic config show --resolved
+  writesonic config show --resolved --engine premium`,
+	Args: cobra.NoArgs,
+	RunE: runConfigShow,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a setting from the user config",
+	Example: `  writesonic config get default_engine
+  writesonic config get rate_limits.premium`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Validate and save a setting in the user config",
+	Example: `  writesonic config set default_engine premium
+  writesonic config set default_copies 3
+  writesonic config set rate_limits.premium 30
+  writesonic config set profiles.acme.default_language de
+  writesonic config set timeout 10m`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:     "unset <key>",
+	Short:   "Remove a setting so its default applies again",
+	Example: `  writesonic config unset default_copies`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runConfigUnset,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List settings in the user config",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigList,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the user config in $EDITOR and validate it on save",
+	Long: `Opens a copy of config.json in $VISUAL or $EDITOR (falling back to vi, or
+notepad on Windows). The file is only replaced once the edited copy parses and
+passes validation; otherwise you can edit again or abort.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEdit,
+}
+
+func init() {
+	configShowCmd.Flags().BoolVar(&configShowResolved, "resolved", false, "Show every effective value and where it came from")
+	configListCmd.Flags().BoolVar(&configListAll, "all", false, "Include unset settings and their descriptions")
+
+	configCmd.AddCommand(configShowCmd, configGetCmd, configSetCmd, configUnsetCmd, configListCmd, configEditCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// configLayers is the user config, selected profile and project file that
+// together make up the effective configuration.
+type configLayers struct {
+	user          *config.Config
+	profile       string
+	profileSource string
+	project       *config.Project
+	effective     *config.Config
+}
+
+// loadConfigLayers loads the user config, discovers the project file from the
+// working directory, selects the profile and merges them.
+func loadConfigLayers() (*configLayers, error) {
+	user, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	l := &configLayers{user: user}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	path, err := config.FindProject(wd)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		if l.project, err = config.LoadProject(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// The project may pick the profile; flags and environment still win.
+	selector := *user
+	projectProfile := ""
+	if l.project != nil {
+		projectProfile, _ = l.project.Values["active_profile"].(string)
+	}
+	if projectProfile != "" {
+		selector.ActiveProfile = projectProfile
+	}
+	l.profile, l.profileSource = profileName(&selector)
+	if projectProfile != "" && l.profileSource == "config file" {
+		l.profileSource = "project " + l.project.Path
+	}
+
+	if l.effective, err = user.WithProfile(l.profile); err != nil {
+		return nil, err
+	}
+	if l.project != nil {
+		if l.effective, err = l.effective.WithOverlay(l.project.Values); err != nil {
+			return nil, fmt.Errorf("%s: %w", l.project.Path, err)
+		}
+	}
+	return l, nil
+}
+
+// builtinSettings are the values used when nothing else sets a setting.
+func builtinSettings() map[string]string {
+	return map[string]string{
+		"default_engine":   "good",
+		"default_language": "en",
+		"default_copies":   "1",
+		"base_url":         writesonic.DefaultBaseURL,
+		"credential_store": config.StoreConfig,
+		"max_retries":      "3",
+		"retry_delay":      "1s",
+		"retry_max_delay":  "30s",
+		"timeout":          "5m0s",
+		"concurrency":      "1",
+		"cache":            "false",
+		"cache_ttl":        "24h0m0s",
+		"disable_history":  "false",
+	}
+}
+
+// settingFlags maps global flags to the settings they override.
+var settingFlags = []struct{ flag, key string }{
+	{"engine", "default_engine"},
+	{"lang", "default_language"},
+	{"copies", "default_copies"},
+	{"base-url", "base_url"},
+	{"proxy", "proxy_url"},
+	{"ca-cert", "ca_cert"},
+	{"client-cert", "client_cert"},
+	{"client-key", "client_key"},
+	{"retries", "max_retries"},
+	{"retry-delay", "retry_delay"},
+	{"retry-max-delay", "retry_max_delay"},
+	{"timeout", "timeout"},
+	{"concurrency", "concurrency"},
+	{"cache", "cache"},
+	{"cache-ttl", "cache_ttl"},
+}
+
+// flagSettings returns the settings overridden by flags on this invocation.
+func flagSettings(cmd *cobra.Command) map[string]string {
+	out := map[string]string{}
+	flags := cmd.Flags()
+	for _, f := range settingFlags {
+		if flags.Changed(f.flag) {
+			out[f.key] = flags.Lookup(f.flag).Value.String()
+		}
+	}
+	if noCacheFlag {
+		out["cache"] = "false"
+	}
+	if noHistoryFlag {
+		out["disable_history"] = "true"
+	}
+	for engine, perMinute := range rateLimitFlag {
+		out["rate_limits."+engine] = fmt.Sprint(perMinute)
+	}
+	return out
+}
+
+type resolvedSetting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// resolve reports the effective value of every known setting and the layer
+// it came from, following the documented precedence.
+func (l *configLayers) resolve(cmd *cobra.Command) ([]resolvedSetting, error) {
+	userValues, err := l.user.Values()
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range []string{"version", "api_key", "profiles", "active_profile"} {
+		delete(userValues, k)
+	}
+	profile, err := l.user.LookupProfile(l.profile)
+	if err != nil {
+		return nil, err
+	}
+	profileValues := map[string]string{}
+	if !config.IsDefaultProfile(l.profile) {
+		p := *profile
+		p.APIKey = ""
+		v, err := (&config.Config{Profile: p}).Values()
+		if err != nil {
+			return nil, err
+		}
+		profileValues = config.Flatten(v)
+	}
+	envValues := map[string]string{}
+	if v := os.Getenv("WRITESONIC_BASE_URL"); v != "" {
+		envValues["base_url"] = v
+	}
+
+	type layer struct {
+		source string
+		values map[string]string
+	}
+	layers := []layer{
+		{"built-in", builtinSettings()},
+		{"user config", config.Flatten(userValues)},
+		{"profile " + l.profile, profileValues},
+	}
+	if l.project != nil {
+		projectValues := map[string]interface{}{}
+		for k, v := range l.project.Values {
+			if k != "active_profile" {
+				projectValues[k] = v
+			}
+		}
+		layers = append(layers, layer{"project " + l.project.Path, config.Flatten(projectValues)})
+	}
+	layers = append(layers,
+		layer{"env WRITESONIC_BASE_URL", envValues},
+		layer{"flag", flagSettings(cmd)},
+	)
+
+	effective := map[string]resolvedSetting{}
+	for _, ly := range layers {
+		for k, v := range ly.values {
+			source := ly.source
+			if source == "flag" {
+				source = "flag " + flagFor(k)
+			}
+			effective[k] = resolvedSetting{Key: k, Value: v, Source: source}
+		}
+	}
+
+	key, keySource, err := resolveAPIKeyFrom(l.effective, l.profile)
+	if err != nil {
+		return nil, err
+	}
+	out := []resolvedSetting{
+		{Key: "profile", Value: l.profile, Source: l.profileSource},
+	}
+	if key != "" {
+		out = append(out, resolvedSetting{Key: "api_key", Value: maskKey(key), Source: keySource})
+	} else {
+		out = append(out, resolvedSetting{Key: "api_key", Value: "(not set)", Source: "-"})
+	}
+	all := map[string]string{}
+	for k := range effective {
+		all[k] = ""
+	}
+	for _, k := range config.SortedKeys(all) {
+		out = append(out, effective[k])
+	}
+	return out, nil
+}
+
+// flagFor returns the flag name that sets key, for display.
+func flagFor(key string) string {
+	switch {
+	case strings.HasPrefix(key, "rate_limits."):
+		return "--rate-limit"
+	case key == "disable_history":
+		return "--no-history"
+	case key == "cache" && noCacheFlag:
+		return "--no-cache"
+	}
+	for _, f := range settingFlags {
+		if f.key == key {
+			return "--" + f.flag
+		}
+	}
+	return ""
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	l, err := loadConfigLayers()
+	if err != nil {
+		return err
+	}
+	if configShowResolved {
+		settings, err := l.resolve(cmd)
+		if err != nil {
+			return err
+		}
+		if output.IsJSON(jsonFlag, prettyFlag) {
+			return output.PrintJSON(settings, prettyFlag)
+		}
+		rows := make([][]string, len(settings))
+		for i, s := range settings {
+			rows[i] = []string{s.Key, s.Value, s.Source}
+		}
+		output.PrintTable([]string{"KEY", "VALUE", "SOURCE"}, rows)
+		return nil
+	}
+
+	userPath, _ := config.ConfigPath()
+	projectPath := "(none)"
+	if l.project != nil {
+		projectPath = l.project.Path
+	}
+	if output.IsJSON(jsonFlag, prettyFlag) {
+		userValues, err := l.user.Values()
+		if err != nil {
+			return err
+		}
+		maskSecrets(userValues)
+		out := map[string]interface{}{"user_config": userPath, "user": userValues, "profile": l.profile}
+		if l.project != nil {
+			out["project_config"] = l.project.Path
+			out["project"] = l.project.Values
+		}
+		return output.PrintJSON(out, prettyFlag)
+	}
+
+	output.PrintKeyValue([][]string{
+		{"User config", userPath},
+		{"Project config", projectPath},
+		{"Profile", fmt.Sprintf("%s (%s)", l.profile, l.profileSource)},
+	})
+	userValues, err := l.user.Values()
+	if err != nil {
+		return err
+	}
+	delete(userValues, "version")
+	maskSecrets(userValues)
+	printSettings("User config", config.Flatten(userValues))
+	if l.project != nil {
+		printSettings("Project config", config.Flatten(l.project.Values))
+	}
+	fmt.Println("\nRun with --resolved to see every effective value and its source.")
+	return nil
+}
+
+func printSettings(title string, values map[string]string) {
+	fmt.Printf("\n%s:\n", title)
+	if len(values) == 0 {
+		fmt.Println("  (empty)")
+		return
+	}
+	for _, k := range config.SortedKeys(values) {
+		fmt.Printf("  %s = %s\n", k, values[k])
+	}
+}
+
+// maskSecrets replaces API keys in config values with their masked form.
+func maskSecrets(values map[string]interface{}) {
+	for k, v := range values {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			maskSecrets(t)
+		case string:
+			if k == "api_key" && t != "" {
+				values[k] = maskKey(t)
+			}
+		}
+	}
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	s, _, err := config.LookupSetting(args[0])
+	if err != nil {
+		return err
+	}
+	v, ok, err := cfg.Get(args[0])
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if def, found := builtinSettings()[args[0]]; found {
+			return fmt.Errorf("%s is not set (default: %s)", args[0], def)
+		}
+		return fmt.Errorf("%s is not set", args[0])
+	}
+	if s.Secret {
+		v = maskKey(config.FormatValue(v))
+	}
+	if jsonFlag || prettyFlag {
+		return output.PrintJSON(v, prettyFlag)
+	}
+	if m, isMap := v.(map[string]interface{}); isMap {
+		return output.PrintJSON(m, true)
+	}
+	fmt.Println(config.FormatValue(v))
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if err := cfg.Set(args[0], args[1]); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	v, _, _ := cfg.Get(args[0])
+	fmt.Printf("%s = %s\n", args[0], config.FormatValue(v))
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	s, _, err := config.LookupSetting(args[0])
+	if err != nil {
+		return err
+	}
+	if s.Secret {
+		return fmt.Errorf("%s can't be unset here; use: writesonic auth logout", args[0])
+	}
+	found, err := cfg.Unset(args[0])
+	if err != nil {
+		return err
+	}
+	if !found {
+		fmt.Printf("%s was not set.\n", args[0])
+		return nil
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	fmt.Printf("%s unset.\n", args[0])
+	return nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	values, err := cfg.Values()
+	if err != nil {
+		return err
+	}
+	delete(values, "version")
+	maskSecrets(values)
+	set := config.Flatten(values)
+
+	type listed struct {
+		Key         string `json:"key"`
+		Value       string `json:"value,omitempty"`
+		Description string `json:"description,omitempty"`
+	}
+	var list []listed
+	seen := map[string]bool{}
+	for _, k := range config.SortedKeys(set) {
+		s, _, err := config.LookupSetting(k)
+		desc := ""
+		if err == nil {
+			desc = s.Description
+		}
+		list = append(list, listed{Key: k, Value: set[k], Description: desc})
+		seen[k] = true
+	}
+	if configListAll {
+		for _, s := range config.Schema {
+			if !seen[s.Key] && !strings.Contains(s.Key, "<") {
+				list = append(list, listed{Key: s.Key, Description: s.Description})
+			}
+		}
+	}
+
+	if output.IsJSON(jsonFlag, prettyFlag) {
+		if list == nil {
+			list = []listed{}
+		}
+		return output.PrintJSON(list, prettyFlag)
+	}
+	if len(list) == 0 {
+		fmt.Println("No settings in the user config. Use --all to see the available keys.")
+		return nil
+	}
+	rows := make([][]string, len(list))
+	for i, l := range list {
+		rows[i] = []string{l.Key, dash(l.Value), l.Description}
+	}
+	output.PrintTable([]string{"KEY", "VALUE", "DESCRIPTION"}, rows)
+	return nil
+}
+
+// editorCommand returns the user's editor as program and arguments.
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	// Load first so an old file is migrated before it is opened.
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	path, err := config.ConfigPath()
+	if err != nil {
+		return err
+	}
+	original, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		original, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "writesonic-config-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(original); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	editor := editorCommand()
+	in := bufio.NewReader(os.Stdin)
+	for {
+		c := exec.Command(editor[0], append(editor[1:], tmp.Name())...)
+		c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := c.Run(); err != nil {
+			return fmt.Errorf("run editor %q: %w", strings.Join(editor, " "), err)
+		}
+		edited, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return fmt.Errorf("read edited config: %w", err)
+		}
+		if bytes.Equal(edited, original) {
+			fmt.Println("No changes.")
+			return nil
+		}
+		verr := validateConfigFile(edited)
+		if verr == nil {
+			if err := output.WriteFileAtomic(path, edited, 0600); err != nil {
+				return fmt.Errorf("save config: %w", err)
+			}
+			fmt.Printf("Saved %s\n", path)
+			return nil
+		}
+
+		fmt.Fprintf(os.Stderr, "%v\n", verr)
+		if !isatty.IsTerminal(os.Stdin.Fd()) {
+			return fmt.Errorf("config not saved")
+		}
+		fmt.Fprint(os.Stderr, "Edit again? [Y/n] ")
+		answer, _ := in.ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a == "n" || a == "no" {
+			return fmt.Errorf("config not saved; %s is unchanged", path)
+		}
+	}
+}
+
+// validateConfigFile checks edited config.json content against the schema.
+func validateConfigFile(data []byte) error {
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if v, ok := values["version"].(float64); !ok || int(v) != config.CurrentVersion {
+		return fmt.Errorf("invalid config:\n  version must be %d", config.CurrentVersion)
+	}
+	var strict config.Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&strict); err != nil {
+		return fmt.Errorf("invalid config:\n  %s", strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return config.ValidateUserValues(values)
+}
@@ -1,11 +1,10 @@
 package cmd
 
 import (
-	"fmt"
-	"net/url"
+	"context"
 
 	"github.com/spf13/cobra"
-	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
 )
 
 // copy.go contains PAS, AIDA, CTA, and bullet-point-answers commands.
@@ -71,56 +70,91 @@ func init() {
 	copyBulletsCmd.Flags().StringVar(&bulletQuestion, "question", "", "Question or topic to answer (required)")
 	copyBulletsCmd.MarkFlagRequired("question")
 
+	addPickFlag(copyPASCmd, copyAIDACmd, copyCTACmd, copyBulletsCmd)
+	addOutputFlags(copyPASCmd, copyAIDACmd, copyCTACmd, copyBulletsCmd)
 	copyCmd.AddCommand(copyPASCmd, copyAIDACmd, copyCTACmd, copyBulletsCmd)
 	rootCmd.AddCommand(copyCmd)
 }
 
 func runCopyPAS(cmd *cobra.Command, args []string) error {
-	return postAndPrint("/pas", map[string]interface{}{
-		"product_name":        pasProductName,
-		"product_description": pasProductDescription,
-	})
+	return printCall(cmd.Context(), call(client.PAS, writesonic.ProductRequest{
+		ProductName:        pasProductName,
+		ProductDescription: pasProductDescription,
+	}))
 }
 
 func runCopyAIDA(cmd *cobra.Command, args []string) error {
-	return postAndPrint("/aida", map[string]interface{}{
-		"product_name":        aidaProductName,
-		"product_description": aidaProductDescription,
-	})
+	return printCall(cmd.Context(), call(client.AIDA, writesonic.ProductRequest{
+		ProductName:        aidaProductName,
+		ProductDescription: aidaProductDescription,
+	}))
 }
 
 func runCopyCTA(cmd *cobra.Command, args []string) error {
-	return postAndPrint("/call-to-action", map[string]interface{}{
-		"product_name": ctaProductName,
-	})
+	return printCall(cmd.Context(), call(client.CallToAction, writesonic.CallToActionRequest{
+		ProductName: ctaProductName,
+	}))
 }
 
 func runCopyBullets(cmd *cobra.Command, args []string) error {
-	return postAndPrint("/bulletpoint-answers", map[string]interface{}{
-		"question": bulletQuestion,
+	return printCall(cmd.Context(), call(client.BulletAnswers, writesonic.BulletAnswersRequest{
+		Question: bulletQuestion,
+	}))
+}
+
+// flagParams returns the --engine, --lang and --copies values as SDK call
+// options.
+func flagParams() writesonic.CallOption {
+	return writesonic.WithParams(writesonic.Params{
+		Engine:   engineFlag,
+		Language: langFlag,
+		Copies:   copiesFlag,
 	})
 }
 
-// postAndPrint is a shared helper for simple text-result endpoints.
-func postAndPrint(path string, body map[string]interface{}) error {
-	params := url.Values{}
-	params.Set("engine", engineFlag)
-	params.Set("language", langFlag)
-	params.Set("num_copies", fmt.Sprintf("%d", copiesFlag))
+// textCall makes one command's request for the given number of copies. The
+// picker calls it again with 1 to regenerate a single copy.
+type textCall func(ctx context.Context, copies int) ([]writesonic.Result, error)
+
+// call binds req to an SDK method, sent with the --engine and --lang values.
+func call[R any](method func(context.Context, R, ...writesonic.CallOption) ([]writesonic.Result, error), req R) textCall {
+	return func(ctx context.Context, copies int) ([]writesonic.Result, error) {
+		return method(ctx, req, writesonic.WithParams(writesonic.Params{
+			Engine:   engineFlag,
+			Language: langFlag,
+			Copies:   copies,
+		}))
+	}
+}
 
-	results, err := client.PostResults(path, params, body)
+// printCall makes the call with --copies and prints its results.
+func printCall(ctx context.Context, c textCall) error {
+	results, err := c(ctx, copiesFlag)
 	if err != nil {
 		return err
 	}
+	return printResults(results, c)
+}
 
-	if output.IsJSON(jsonFlag, prettyFlag) {
-		return output.PrintJSON(results, prettyFlag)
+// printResults prints text results in the --format format, or saves them
+// with --output/--output-dir. With --pick the user chooses among them first;
+// regenerate, if not nil, is the call that produced them.
+func printResults(results []writesonic.Result, regenerate textCall) error {
+	if pickFlag {
+		var err error
+		if results, err = pickResults(results, regenerate); err != nil {
+			return err
+		}
 	}
-
-	texts := make([]string, len(results))
-	for i, r := range results {
-		texts[i] = r.Text
+	if shell != nil {
+		texts := make([]string, len(results))
+		for i, r := range results {
+			texts[i] = r.Text
+		}
+		rememberResults(texts)
+	}
+	if saveRequested() {
+		return saveResults(results)
 	}
-	output.PrintText(texts)
-	return nil
+	return renderResults(resultsDocument(results), len(results) > 0 && results[0].Cached)
 }
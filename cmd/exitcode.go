@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/the20100/writesonic-cli/internal/pick"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+// Exit codes returned by the writesonic binary. They are part of the CLI's
+// public contract so shell pipelines can branch on the failure class.
+const (
+	ExitOK          = 0   // success
+	ExitError       = 1   // unclassified failure (bad flags, I/O, decode errors)
+	ExitAuth        = 3   // missing or rejected API key (HTTP 401/403)
+	ExitQuota       = 4   // out of credits or rate limited (HTTP 402/429)
+	ExitValidation  = 5   // request rejected as invalid (HTTP 422 or client-side checks)
+	ExitServer      = 6   // Writesonic server error (HTTP 5xx)
+	ExitNetwork     = 7   // connection, DNS, TLS or timeout failure
+	ExitInterrupted = 130 // cancelled by SIGINT/SIGTERM, or --pick quit without choosing
+)
+
+// errNoAPIKey is returned when no key is configured for an API command.
+var errNoAPIKey = errors.New("no API key found — run: writesonic auth set-key\n" +
+	"Or set the WRITESONIC_API_KEY environment variable")
+
+// exitCode maps an error returned by a command to a process exit status.
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	if errors.Is(err, errNoAPIKey) {
+		return ExitAuth
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, pick.ErrCancelled) {
+		return ExitInterrupted
+	}
+	switch writesonic.Classify(err) {
+	case writesonic.ClassAuth:
+		return ExitAuth
+	case writesonic.ClassQuota:
+		return ExitQuota
+	case writesonic.ClassValidation:
+		return ExitValidation
+	case writesonic.ClassServer:
+		return ExitServer
+	case writesonic.ClassNetwork:
+		return ExitNetwork
+	}
+	return ExitError
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+var formatFlag string
+
+// outputFormat returns the format for generated results: --format if set,
+// else json for --json/--pretty, else json when stdout isn't a terminal and
+// text when it is. Files never auto-switch, since the terminal check is
+// about stdout.
+func outputFormat(toFile bool) (output.Format, error) {
+	name := formatFlag
+	switch {
+	case name != "":
+		if (jsonFlag || prettyFlag) && name != "json" {
+			return output.Format{}, fmt.Errorf("--json and --pretty can't be combined with --format %s", name)
+		}
+	case jsonFlag || prettyFlag:
+		name = "json"
+	case !toFile && output.IsJSON(false, false):
+		name = "json"
+	default:
+		name = "text"
+	}
+	f, err := output.LookupFormat(name)
+	if err != nil {
+		return output.Format{}, fmt.Errorf("--format: %w", err)
+	}
+	return f, nil
+}
+
+// renderResults writes a document to stdout in the chosen format.
+func renderResults(d output.Document, cached bool) error {
+	f, err := outputFormat(false)
+	if err != nil {
+		return err
+	}
+	if cached && f.Name == "text" {
+		fmt.Fprintln(os.Stderr, "(served from cache)")
+	}
+	d.Pretty = prettyFlag
+	return f.Render(os.Stdout, d)
+}
+
+// resultsDocument wraps text results for the formatters.
+func resultsDocument(results []writesonic.Result) output.Document {
+	var d output.Document
+	for _, r := range results {
+		d.Items = append(d.Items, r)
+		d.Records = append(d.Records, output.Record{
+			{Key: "text", Label: "Text", Value: strings.TrimRight(r.Text, "\n"), Role: output.RoleBody},
+		})
+	}
+	return d
+}
+
+// landingDocument wraps landing pages for the formatters.
+func landingDocument(pages []writesonic.LandingPage) output.Document {
+	var d output.Document
+	for _, p := range pages {
+		d.Items = append(d.Items, p)
+		d.Records = append(d.Records, landingRecord(p))
+	}
+	return d
+}
+
+// landingMarkdown renders each landing page as Markdown, for commands that
+// pass pages on as text.
+func landingMarkdown(pages []writesonic.LandingPage) ([]string, error) {
+	md, _ := output.LookupFormat("markdown")
+	texts := make([]string, 0, len(pages))
+	for _, p := range pages {
+		var buf bytes.Buffer
+		if err := md.Render(&buf, landingDocument([]writesonic.LandingPage{p})); err != nil {
+			return nil, err
+		}
+		texts = append(texts, strings.TrimRight(buf.String(), "\n"))
+	}
+	return texts, nil
+}
+
+// landingRecord lists a landing page's fields in page order.
+func landingRecord(p writesonic.LandingPage) output.Record {
+	return output.Record{
+		{Key: "title", Label: "Title", Value: p.Title, Role: output.RoleTitle},
+		{Key: "subtitle", Label: "Subtitle", Value: p.Subtitle, Role: output.RoleParagraph},
+		{Key: "main_feature_title", Label: "Main Feature Title", Value: p.MainFeatureTitle, Role: output.RoleHeading},
+		{Key: "main_feature_subtitle", Label: "Main Feature Subtitle", Value: p.MainFeatureSubtitle, Role: output.RoleParagraph},
+		{Key: "feature_1_title", Label: "Feature 1 Title", Value: p.Feature1Title, Role: output.RoleSubheading},
+		{Key: "feature_1_subtitle", Label: "Feature 1 Subtitle", Value: p.Feature1Subtitle, Role: output.RoleParagraph},
+		{Key: "feature_2_title", Label: "Feature 2 Title", Value: p.Feature2Title, Role: output.RoleSubheading},
+		{Key: "feature_2_subtitle", Label: "Feature 2 Subtitle", Value: p.Feature2Subtitle, Role: output.RoleParagraph},
+		{Key: "feature_3_title", Label: "Feature 3 Title", Value: p.Feature3Title, Role: output.RoleSubheading},
+		{Key: "feature_3_subtitle", Label: "Feature 3 Subtitle", Value: p.Feature3Subtitle, Role: output.RoleParagraph},
+		{Key: "cta", Label: "CTA", Value: p.CTA, Role: output.RoleCTA},
+		{Key: "button", Label: "Button", Value: p.Button, Role: output.RoleButton},
+	}
+}
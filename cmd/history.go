@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/internal/config"
+	"github.com/the20100/writesonic-cli/internal/history"
+	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+var (
+	historyCommand string
+	historySince   string
+	historyUntil   string
+	historyLimit   int
+
+	historyPruneOlderThan string
+	historyPruneKeep      int
+	historyPruneAll       bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Browse, search and replay previously generated content",
+	Long: `Every successful request and its response are recorded locally in
+history.jsonl inside the config directory. Use --no-history (or set
+"disable_history": true in the config file) to opt out.`,
+	Annotations: map[string]string{annotationOffline: "true"},
+}
+
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded requests, newest first",
+	Example: `  writesonic history list
+  writesonic history list --command "copy cta" --since 7d
+  writesonic history list --since 2025-01-01 --until 2025-02-01 --limit 0`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryList,
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:     "show <id>",
+	Short:   "Show a recorded request and its results",
+	Example: `  writesonic history show 20250114T093012-1a2b3c`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runHistoryShow,
+}
+
+var historySearchCmd = &cobra.Command{
+	Use:   "search <text>",
+	Short: "Search request fields and generated text",
+	Example: `  writesonic history search "remote work"
+  writesonic history search pricing --command landing --since 30d`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistorySearch,
+}
+
+var historyReplayCmd = &cobra.Command{
+	Use:   "replay <id>",
+	Short: "Send a recorded request again (billed as a new request)",
+	Long: `Replay sends the recorded endpoint and body again. The recorded engine,
+language and copies are used unless --engine, --lang or --copies are given.
+The new entry is recorded under the original command, with "replayed_from"
+set to the ID it was replayed from.`,
+	Example: `  writesonic history replay 20250114T093012
+  writesonic history replay 20250114T093012 --engine premium`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationOffline: "false"},
+	RunE:        runHistoryReplay,
+}
+
+var historyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export recorded entries as JSONL",
+	Example: `  writesonic history export > history-backup.jsonl
+  writesonic history export --command article --since 2025-01-01`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryExport,
+}
+
+var historyPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete old history entries",
+	Example: `  writesonic history prune --older-than 90d
+  writesonic history prune --keep 500
+  writesonic history prune --all`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryPrune,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{historyListCmd, historySearchCmd, historyExportCmd} {
+		c.Flags().StringVar(&historyCommand, "command", "", `Only entries for this command or group (e.g. "copy cta", "rewrite")`)
+		c.Flags().StringVar(&historySince, "since", "", "Only entries at or after this date, timestamp or age (e.g. 2025-01-31, 7d)")
+		c.Flags().StringVar(&historyUntil, "until", "", "Only entries before this date, timestamp or age")
+	}
+	historyListCmd.Flags().IntVar(&historyLimit, "limit", 20, "Maximum entries to show (0 for all)")
+	historySearchCmd.Flags().IntVar(&historyLimit, "limit", 20, "Maximum entries to show (0 for all)")
+
+	historyPruneCmd.Flags().StringVar(&historyPruneOlderThan, "older-than", "", "Delete entries older than this age or date (e.g. 90d, 2025-01-01)")
+	historyPruneCmd.Flags().IntVar(&historyPruneKeep, "keep", 0, "Keep only the N most recent entries")
+	historyPruneCmd.Flags().BoolVar(&historyPruneAll, "all", false, "Delete all history")
+
+	addPickFlag(historyReplayCmd)
+	addOutputFlags(historyReplayCmd)
+	historyCmd.AddCommand(historyListCmd, historyShowCmd, historySearchCmd,
+		historyReplayCmd, historyExportCmd, historyPruneCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+// historyStore returns the store in the config directory.
+func historyStore() (*history.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return history.Open(filepath.Join(dir, "history.jsonl")), nil
+}
+
+// replaying is the entry being replayed by history replay, so the new entry is
+// recorded under the original command rather than "history replay".
+var replaying *history.Entry
+
+// recordHistory returns a client observer that appends each successful
+// request to the history store, labelled with the running command. Failures
+// are reported but never fatal.
+func recordHistory() func(writesonic.Exchange) {
+	store, err := historyStore()
+	if err != nil {
+		return func(writesonic.Exchange) {}
+	}
+	return func(ex writesonic.Exchange) {
+		copies, _ := strconv.Atoi(ex.Params.Get("num_copies"))
+		e := &history.Entry{
+			Command:  commandName(runningCmd),
+			Endpoint: ex.Path,
+			Engine:   ex.Params.Get("engine"),
+			Language: ex.Params.Get("language"),
+			Copies:   copies,
+			Body:     ex.Body,
+			Results:  json.RawMessage(ex.Response),
+		}
+		if replaying != nil {
+			e.Command, e.ReplayedFrom = replaying.Command, replaying.ID
+		}
+		if err := store.Append(e); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not record history: %v\n", err)
+		}
+	}
+}
+
+// historyFilter builds a filter from the shared --command/--since/--until flags.
+func historyFilter(text string) (history.Filter, error) {
+	now := time.Now()
+	since, err := history.ParseTime(historySince, now)
+	if err != nil {
+		return history.Filter{}, fmt.Errorf("--since: %w", err)
+	}
+	until, err := history.ParseTime(historyUntil, now)
+	if err != nil {
+		return history.Filter{}, fmt.Errorf("--until: %w", err)
+	}
+	return history.Filter{Command: historyCommand, Since: since, Until: until, Text: text}, nil
+}
+
+// loadHistory returns matching entries, newest first, capped at limit (0 = all).
+func loadHistory(f history.Filter, limit int) ([]history.Entry, error) {
+	store, err := historyStore()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := store.All()
+	if err != nil {
+		return nil, err
+	}
+	entries = f.Apply(entries)
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func runHistoryList(cmd *cobra.Command, args []string) error {
+	f, err := historyFilter("")
+	if err != nil {
+		return err
+	}
+	entries, err := loadHistory(f, historyLimit)
+	if err != nil {
+		return err
+	}
+	return printHistoryEntries(entries)
+}
+
+func runHistorySearch(cmd *cobra.Command, args []string) error {
+	f, err := historyFilter(args[0])
+	if err != nil {
+		return err
+	}
+	entries, err := loadHistory(f, historyLimit)
+	if err != nil {
+		return err
+	}
+	return printHistoryEntries(entries)
+}
+
+func printHistoryEntries(entries []history.Entry) error {
+	if output.IsJSON(jsonFlag, prettyFlag) {
+		if entries == nil {
+			entries = []history.Entry{}
+		}
+		return output.PrintJSON(entries, prettyFlag)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No history entries found.")
+		return nil
+	}
+	rows := make([][]string, len(entries))
+	for i, e := range entries {
+		preview := strings.Join(strings.Fields(e.Preview()), " ")
+		rows[i] = []string{
+			e.ID,
+			e.Time.Local().Format("2006-01-02 15:04"),
+			e.Command,
+			e.Engine,
+			output.Truncate(preview, 60),
+		}
+	}
+	output.PrintTable([]string{"ID", "TIME", "COMMAND", "ENGINE", "PREVIEW"}, rows)
+	return nil
+}
+
+func runHistoryShow(cmd *cobra.Command, args []string) error {
+	store, err := historyStore()
+	if err != nil {
+		return err
+	}
+	e, err := store.Find(args[0])
+	if err != nil {
+		return err
+	}
+	if output.IsJSON(jsonFlag, prettyFlag) {
+		return output.PrintJSON(e, prettyFlag)
+	}
+
+	body, _ := json.Marshal(e.Body)
+	rows := [][]string{
+		{"ID", e.ID},
+		{"Time", e.Time.Local().Format(time.RFC1123)},
+		{"Command", e.Command},
+	}
+	if e.ReplayedFrom != "" {
+		rows = append(rows, []string{"Replayed from", e.ReplayedFrom})
+	}
+	output.PrintKeyValue(append(rows, [][]string{
+		{"Endpoint", e.Endpoint},
+		{"Engine", e.Engine},
+		{"Language", e.Language},
+		{"Copies", strconv.Itoa(e.Copies)},
+		{"Request", string(body)},
+	}...))
+	fmt.Println()
+	return printHistoryResults(e, nil)
+}
+
+// printHistoryResults prints an entry's stored response like the original
+// command did; regenerate is passed on to the picker.
+func printHistoryResults(e *history.Entry, regenerate textCall) error {
+	if e.Endpoint == "/landing-pages" {
+		var pages []writesonic.LandingPage
+		if err := json.Unmarshal(e.Results, &pages); err != nil {
+			return fmt.Errorf("decode stored results: %w", err)
+		}
+		return printLandingPages(pages)
+	}
+	var results []writesonic.Result
+	if err := json.Unmarshal(e.Results, &results); err != nil {
+		return fmt.Errorf("decode stored results: %w", err)
+	}
+	return printResults(results, regenerate)
+}
+
+func runHistoryReplay(cmd *cobra.Command, args []string) error {
+	store, err := historyStore()
+	if err != nil {
+		return err
+	}
+	e, err := store.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("engine", e.Engine)
+	params.Set("language", e.Language)
+	params.Set("num_copies", strconv.Itoa(e.Copies))
+	flags := cmd.Flags()
+	if flags.Changed("engine") || e.Engine == "" {
+		params.Set("engine", engineFlag)
+	}
+	if flags.Changed("lang") || e.Language == "" {
+		params.Set("language", langFlag)
+	}
+	if flags.Changed("copies") || e.Copies == 0 {
+		params.Set("num_copies", strconv.Itoa(copiesFlag))
+	}
+
+	replaying = e
+	defer func() { replaying = nil }()
+	data, err := client.Post(cmd.Context(), e.Endpoint, params, e.Body)
+	if err != nil {
+		return err
+	}
+	replayed := &history.Entry{Endpoint: e.Endpoint, Results: data}
+	return printHistoryResults(replayed, func(ctx context.Context, copies int) ([]writesonic.Result, error) {
+		return client.Call(ctx, e.Endpoint, e.Body, writesonic.WithParams(writesonic.Params{
+			Engine:   params.Get("engine"),
+			Language: params.Get("language"),
+			Copies:   copies,
+		}))
+	})
+}
+
+func runHistoryExport(cmd *cobra.Command, args []string) error {
+	f, err := historyFilter("")
+	if err != nil {
+		return err
+	}
+	store, err := historyStore()
+	if err != nil {
+		return err
+	}
+	entries, err := store.All()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for _, e := range f.Apply(entries) {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runHistoryPrune(cmd *cobra.Command, args []string) error {
+	if !historyPruneAll && historyPruneOlderThan == "" && historyPruneKeep <= 0 {
+		return fmt.Errorf("specify --older-than, --keep or --all")
+	}
+	store, err := historyStore()
+	if err != nil {
+		return err
+	}
+	entries, err := store.All()
+	if err != nil {
+		return err
+	}
+
+	kept := entries
+	if historyPruneAll {
+		kept = nil
+	}
+	if historyPruneOlderThan != "" {
+		cutoff, err := history.ParseTime(historyPruneOlderThan, time.Now())
+		if err != nil {
+			return fmt.Errorf("--older-than: %w", err)
+		}
+		kept = history.Filter{Since: cutoff}.Apply(kept)
+	}
+	if historyPruneKeep > 0 && len(kept) > historyPruneKeep {
+		kept = kept[len(kept)-historyPruneKeep:]
+	}
+
+	if err := store.Replace(kept); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d entries, %d remaining.\n", len(entries)-len(kept), len(kept))
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// textInput describes where a long-text flag can get its value from.
+type textInput struct {
+	flag  string // flag name, e.g. "content"
+	value string // flag value; "-" reads stdin
+	file  string // value of the matching --<flag>-file flag
+	args  []string
+	// implicitStdin reads stdin when no other source is given and stdin is
+	// a pipe or a redirected file.
+	implicitStdin bool
+}
+
+// read resolves the text from exactly one source: the flag value, "-" for
+// stdin, --<flag>-file, positional file arguments, or piped stdin.
+func (in textInput) read() (string, error) {
+	sources := 0
+	for _, set := range []bool{in.value != "", in.file != "", len(in.args) > 0} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return "", fmt.Errorf("use only one of --%s, --%s-file or file arguments", in.flag, in.flag)
+	}
+
+	switch {
+	case in.value == "-":
+		return readStdin()
+	case in.value != "":
+		return in.value, nil
+	case in.file != "":
+		return readTextFile(in.file)
+	case len(in.args) > 0:
+		parts := make([]string, 0, len(in.args))
+		for _, path := range in.args {
+			text, err := readTextFile(path)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, text)
+		}
+		return strings.Join(parts, "\n\n"), nil
+	case in.implicitStdin && stdinPiped():
+		return readStdin()
+	}
+	return "", nil
+}
+
+// require is like read but fails when no source provided any text.
+func (in textInput) require() (string, error) {
+	text, err := in.read()
+	if err != nil {
+		return "", err
+	}
+	if text == "" {
+		return "", fmt.Errorf("--%s is required (or use --%s -, --%s-file, or pipe text on stdin)",
+			in.flag, in.flag, in.flag)
+	}
+	return text, nil
+}
+
+// stdinPiped reports whether stdin is a pipe or a regular file. Anything
+// else (a terminal, /dev/null, or an inherited socket under cron, CI or ssh)
+// is not read unless asked for with "-", since reading it could block forever.
+func stdinPiped() bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	mode := fi.Mode()
+	return mode&os.ModeNamedPipe != 0 || mode.IsRegular()
+}
+
+func readStdin() (string, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readTextFile(path string) (string, error) {
+	if path == "-" {
+		return readStdin()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
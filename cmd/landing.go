@@ -1,11 +1,8 @@
 package cmd
 
 import (
-	"fmt"
-	"net/url"
-
 	"github.com/spf13/cobra"
-	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
 )
 
 var (
@@ -55,82 +52,49 @@ func init() {
 	landingHeadlineCmd.MarkFlagRequired("name")
 	landingHeadlineCmd.MarkFlagRequired("desc")
 
+	addPickFlag(landingHeadlineCmd)
+	addOutputFlags(landingPageCmd, landingHeadlineCmd)
 	landingCmd.AddCommand(landingPageCmd, landingHeadlineCmd)
 	rootCmd.AddCommand(landingCmd)
 }
 
 func runLandingPage(cmd *cobra.Command, args []string) error {
-	params := url.Values{}
-	params.Set("engine", engineFlag)
-	params.Set("language", langFlag)
-	params.Set("num_copies", fmt.Sprintf("%d", copiesFlag))
-
-	body := map[string]interface{}{
-		"product_name":        landingProductName,
-		"product_description": landingProductDescription,
-		"feature_1":           landingFeature1,
-		"feature_2":           landingFeature2,
-		"feature_3":           landingFeature3,
-	}
-
-	results, err := client.PostLandingPages(params, body)
+	results, err := client.LandingPage(cmd.Context(), writesonic.LandingPageRequest{
+		ProductName:        landingProductName,
+		ProductDescription: landingProductDescription,
+		Feature1:           landingFeature1,
+		Feature2:           landingFeature2,
+		Feature3:           landingFeature3,
+	}, flagParams())
 	if err != nil {
 		return err
 	}
 
-	if output.IsJSON(jsonFlag, prettyFlag) {
-		return output.PrintJSON(results, prettyFlag)
-	}
-
-	for i, r := range results {
-		if len(results) > 1 {
-			fmt.Printf("--- Result %d ---\n\n", i+1)
-		}
-		output.PrintKeyValue([][]string{
-			{"Title", r.Title},
-			{"Subtitle", r.Subtitle},
-			{"Main Feature Title", r.MainFeatureTitle},
-			{"Main Feature Subtitle", r.MainFeatureSubtitle},
-			{"Feature 1 Title", r.Feature1Title},
-			{"Feature 1 Subtitle", r.Feature1Subtitle},
-			{"Feature 2 Title", r.Feature2Title},
-			{"Feature 2 Subtitle", r.Feature2Subtitle},
-			{"Feature 3 Title", r.Feature3Title},
-			{"Feature 3 Subtitle", r.Feature3Subtitle},
-			{"CTA", r.CTA},
-			{"Button", r.Button},
-		})
-		if i < len(results)-1 {
-			fmt.Println()
-		}
-	}
-	return nil
+	return printLandingPages(results)
 }
 
-func runLandingHeadline(cmd *cobra.Command, args []string) error {
-	params := url.Values{}
-	params.Set("engine", engineFlag)
-	params.Set("language", langFlag)
-	params.Set("num_copies", fmt.Sprintf("%d", copiesFlag))
-
-	body := map[string]interface{}{
-		"product_name":        headlineProductName,
-		"product_description": headlineProductDescription,
+// printLandingPages prints landing pages in the --format format, or saves
+// them with --output/--output-dir.
+func printLandingPages(results []writesonic.LandingPage) error {
+	if pickFlag {
+		return errPickPages
 	}
-
-	results, err := client.PostResults("/landing-page-headlines", params, body)
-	if err != nil {
-		return err
+	if shell != nil {
+		texts, err := landingMarkdown(results)
+		if err != nil {
+			return err
+		}
+		rememberResults(texts)
 	}
-
-	if output.IsJSON(jsonFlag, prettyFlag) {
-		return output.PrintJSON(results, prettyFlag)
+	if saveRequested() {
+		return saveLandingPages(results)
 	}
+	return renderResults(landingDocument(results), len(results) > 0 && results[0].Cached)
+}
 
-	texts := make([]string, len(results))
-	for i, r := range results {
-		texts[i] = r.Text
-	}
-	output.PrintText(texts)
-	return nil
+func runLandingHeadline(cmd *cobra.Command, args []string) error {
+	return printCall(cmd.Context(), call(client.LandingHeadlines, writesonic.ProductRequest{
+		ProductName:        headlineProductName,
+		ProductDescription: headlineProductDescription,
+	}))
 }
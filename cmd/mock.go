@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/internal/mock"
+)
+
+var (
+	mockAddr    string
+	mockLatency time.Duration
+	mockJitter  time.Duration
+	mockFail    []string
+	mockHang    time.Duration
+	mockSeed    int64
+	mockQuiet   bool
+)
+
+var mockServerCmd = &cobra.Command{
+	Use:   "mock-server",
+	Short: "Run a fake Writesonic API for local development and tests",
+	Long: `Serve every endpoint the CLI uses with deterministic, correctly shaped fake
+responses. num_copies is honored, requests are validated like the real API
+(missing key → 401, bad fields → 422), and latency and failures can be injected
+to exercise retries and error handling. Nothing is billed.
+
+Point the CLI at it with --base-url or WRITESONIC_BASE_URL; any API key works.
+
+Failure specs (--fail, repeatable, first match wins):
+  429              every request gets HTTP 429 (with Retry-After: 1)
+  500:2            the first 2 requests get HTTP 500, later ones succeed
+  503:25%          a quarter of requests get HTTP 503
+  /blog-ideas=422  only /blog-ideas fails, with a 422 validation error
+  timeout:1        the first request hangs until the client gives up`,
+	Example: `  writesonic mock-server
+  WRITESONIC_BASE_URL=http://127.0.0.1:8089 WRITESONIC_API_KEY=test writesonic blog-ideas --topic AI
+  writesonic mock-server --latency 300ms --jitter 200ms --fail 429:2 --fail 500:10%`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationOffline: "true"},
+	RunE:        runMockServer,
+}
+
+func init() {
+	mockServerCmd.Flags().StringVar(&mockAddr, "addr", "127.0.0.1:8089", "Address to listen on")
+	mockServerCmd.Flags().DurationVar(&mockLatency, "latency", 0, "Delay added to every response")
+	mockServerCmd.Flags().DurationVar(&mockJitter, "jitter", 0, "Random extra delay, up to this much")
+	mockServerCmd.Flags().StringArrayVar(&mockFail, "fail", nil, "Inject failures: [PATH=]STATUS|timeout[:COUNT|:PERCENT%] (repeatable)")
+	mockServerCmd.Flags().DurationVar(&mockHang, "hang", 10*time.Minute, "How long a timeout failure holds the connection")
+	mockServerCmd.Flags().Int64Var(&mockSeed, "seed", 1, "Seed for random failures and jitter")
+	mockServerCmd.Flags().BoolVar(&mockQuiet, "quiet", false, "Don't log requests to stderr")
+	rootCmd.AddCommand(mockServerCmd)
+}
+
+func runMockServer(cmd *cobra.Command, args []string) error {
+	opts := mock.Options{
+		Latency: mockLatency,
+		Jitter:  mockJitter,
+		Hang:    mockHang,
+		Seed:    mockSeed,
+	}
+	if !mockQuiet {
+		opts.Log = os.Stderr
+	}
+	for _, spec := range mockFail {
+		f, err := mock.ParseFailure(spec)
+		if err != nil {
+			return fmt.Errorf("--fail: %w", err)
+		}
+		opts.Failures = append(opts.Failures, f)
+	}
+
+	ln, err := net.Listen("tcp", mockAddr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: mock.NewHandler(opts)}
+	fmt.Fprintf(os.Stderr, "Mock Writesonic API listening on http://%s (Ctrl-C to stop)\n", ln.Addr())
+	for _, f := range opts.Failures {
+		fmt.Fprintf(os.Stderr, "  injecting failure: %s\n", f)
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-cmd.Context().Done():
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/internal/pick"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+var pickFlag bool
+
+// errPickPages is returned for --pick on landing pages, which are whole pages
+// rather than one text per copy.
+var errPickPages = errors.New("--pick works with text results; landing page returns whole pages (use landing headline)")
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui <command> [flags]",
+	Short: "Run a command and pick among its copies in a full-screen view",
+	Long: `Run a command and open the picker on its results; the same as adding --pick.
+
+The copies are shown side by side. Keys:
+  ←/→, tab, 1-9   move between copies
+  ↑/↓, PgUp/PgDn  scroll the current copy
+  space           star or unstar it (a stars all)
+  e               edit it in place (ctrl+s saves, esc discards)
+  r               regenerate just this copy
+  enter           emit the starred copies, or the current one if none is starred
+  q, esc          quit without output
+
+Only the chosen copies are printed (or written with --output/--output-dir), in
+the usual --format, so the choice can feed a script. The picker draws on
+stderr, which must be a terminal; stdout may be piped.`,
+	Example: `  writesonic tui landing headline --name CloudStore --desc "Cloud storage" --copies 5
+  writesonic copy cta --name CloudStore --copies 5 --pick --format text > cta.txt`,
+	Annotations:        map[string]string{annotationOffline: "true"},
+	DisableFlagParsing: true,
+	RunE:               runTUI,
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
+
+// addPickFlag adds --pick to commands that print text results. Commands
+// that write elsewhere (batch, run, pipeline) don't take it.
+func addPickFlag(cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		c.Flags().BoolVar(&pickFlag, "pick", false, "Choose among the copies in a full-screen picker before output")
+	}
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+		return cmd.Help()
+	}
+	target, _, err := rootCmd.Find(args)
+	if err != nil {
+		return err
+	}
+	if target == cmd || target == rootCmd {
+		return fmt.Errorf("tui needs a command that generates text, e.g. landing headline")
+	}
+	if target.Flags().Lookup("pick") == nil {
+		return fmt.Errorf("%s doesn't print copies to pick from", commandName(target))
+	}
+	rootCmd.SetArgs(append(args, "--pick"))
+	_, err = rootCmd.ExecuteContextC(cmd.Context())
+	return err
+}
+
+// checkPick fails early, before any credits are spent, when --pick can't
+// show the picker.
+func checkPick() error {
+	if pickFlag && !isatty.IsTerminal(os.Stderr.Fd()) {
+		return fmt.Errorf("--pick needs a terminal on stderr")
+	}
+	return nil
+}
+
+// pickResults lets the user choose among results and returns the chosen ones.
+// Edited and regenerated copies are returned with their new text. A copy is
+// regenerated by making regenerate again for one copy; nil disables it.
+func pickResults(results []writesonic.Result, regenerate textCall) ([]writesonic.Result, error) {
+	texts := make([]string, len(results))
+	for i, r := range results {
+		texts[i] = r.Text
+	}
+	opts := pick.Options{Title: commandName(runningCmd), Output: os.Stderr}
+	if regenerate != nil {
+		opts.Regenerate = func(ctx context.Context, i int) (string, error) {
+			rs, err := regenerate(writesonic.WithoutCache(ctx), 1)
+			if err != nil {
+				return "", err
+			}
+			if len(rs) == 0 {
+				return "", fmt.Errorf("no result returned")
+			}
+			return rs[0].Text, nil
+		}
+	}
+
+	choices, err := pick.Run(runningCmd.Context(), texts, opts)
+	if err != nil {
+		return nil, err
+	}
+	chosen := make([]writesonic.Result, len(choices))
+	for i, c := range choices {
+		chosen[i] = results[c.Index]
+		if c.Changed {
+			chosen[i] = writesonic.Result{Text: c.Text}
+		}
+	}
+	return chosen, nil
+}
@@ -0,0 +1,523 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/internal/site"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+// pipeline.go chains several endpoints into one finished piece of content.
+
+var (
+	pipelineTopic     string
+	pipelineKeyword   string
+	pipelineIdeas     int
+	pipelineIdea      int
+	pipelineTitle     string
+	pipelineSections  string
+	pipelineIntro     string
+	pipelineIntroFile string
+	pipelineRestart   bool
+)
+
+// maxGeneratedSections caps the outline asked of /bulletpoint-answers.
+const maxGeneratedSections = 8
+
+var pipelineCmd = &cobra.Command{
+	Use:   "pipeline",
+	Short: "Run multi-step generation chains",
+}
+
+var pipelineBlogCmd = &cobra.Command{
+	Use:   "blog",
+	Short: "Generate a finished blog post: ideas → outline → article → meta → conclusion",
+	Long: `Run the whole blog chain in one go:
+
+  1. ideas       /blog-ideas for --topic (skipped with --title)
+  2. pick        choose an idea: --idea N, a prompt on a terminal, else the first
+  3. outline     section titles from /bulletpoint-answers (skipped with --sections)
+  4. intro       an introduction from /paragraph-writer (skipped with --intro)
+  5. article     /ai-article-writer-v3 with the title, intro and sections
+  6. meta        SEO title and description from /meta-blog
+  7. conclusion  /conclusion-writer
+
+The assembled post is written as Markdown with front matter. The document is
+<slug>.md (from the chosen title) in the current directory, in --output-dir,
+or at --output.
+
+Every request and result goes into a JSON manifest, saved after each step:
+<name>.manifest.json next to --output, or named after --title (else --topic)
+in the output directory. Rerunning with the same inputs reuses the steps
+recorded there instead of paying for them again, so a failed run resumes at
+the step that failed; --restart generates everything anew. With
+--on-exists skip, an existing document and its manifest are left untouched.`,
+	Example: `  writesonic pipeline blog --topic "remote work"
+  writesonic pipeline blog --topic "remote work" --keyword "async" --idea 2 --output-dir posts
+  writesonic pipeline blog --topic "remote work" --title "Async Standups That Work" --sections "Why,How,Tools"`,
+	Args: cobra.NoArgs,
+	RunE: runPipelineBlog,
+}
+
+func init() {
+	pipelineBlogCmd.Flags().StringVar(&pipelineTopic, "topic", "", "Topic of the post (required)")
+	pipelineBlogCmd.Flags().StringVar(&pipelineKeyword, "keyword", "", "Primary keyword for the ideas, also used as a tag")
+	pipelineBlogCmd.Flags().IntVar(&pipelineIdeas, "ideas", 3, "Number of ideas to generate (1-5)")
+	pipelineBlogCmd.Flags().IntVar(&pipelineIdea, "idea", 0, "Pick idea N without prompting (default: prompt on a terminal, else 1)")
+	pipelineBlogCmd.Flags().StringVar(&pipelineTitle, "title", "", "Use this title instead of generating ideas")
+	pipelineBlogCmd.Flags().StringVar(&pipelineSections, "sections", "", "Comma-separated section titles instead of a generated outline")
+	pipelineBlogCmd.Flags().StringVar(&pipelineIntro, "intro", "", "Introduction instead of a generated one (- for stdin)")
+	pipelineBlogCmd.Flags().StringVar(&pipelineIntroFile, "intro-file", "", "Read the introduction from a file")
+	pipelineBlogCmd.Flags().BoolVar(&pipelineRestart, "restart", false, "Ignore the manifest of an earlier run and generate every step again")
+	pipelineBlogCmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Write the post to this file (default <slug>.md)")
+	pipelineBlogCmd.Flags().StringVar(&outputDirFlag, "output-dir", "", "Write the post into this directory")
+	pipelineBlogCmd.Flags().StringVar(&onExistsFlag, "on-exists", string(output.Overwrite), "When the post exists: overwrite, skip or suffix")
+	pipelineBlogCmd.MarkFlagRequired("topic")
+
+	pipelineCmd.AddCommand(pipelineBlogCmd)
+	rootCmd.AddCommand(pipelineCmd)
+}
+
+// pipelineManifest records every step of a pipeline run.
+type pipelineManifest struct {
+	Pipeline string         `json:"pipeline"`
+	Topic    string         `json:"topic"`
+	Keyword  string         `json:"keyword,omitempty"`
+	Engine   string         `json:"engine"`
+	Language string         `json:"language"`
+	Created  time.Time      `json:"created"`
+	Title    string         `json:"title"`
+	Document string         `json:"document"`
+	Steps    []pipelineStep `json:"steps"`
+}
+
+// pipelineStep is one step's input and output. Endpoint is empty for steps
+// satisfied from flags.
+type pipelineStep struct {
+	Name     string      `json:"name"`
+	Endpoint string      `json:"endpoint,omitempty"`
+	Request  interface{} `json:"request,omitempty"`
+	Results  []string    `json:"results,omitempty"`
+	Selected string      `json:"selected,omitempty"`
+	Reused   bool        `json:"reused,omitempty"` // taken from an earlier run's manifest
+}
+
+// pipelineRun is a run in progress: its manifest, saved after every step
+// that called the API, and the manifest of an earlier run with the same
+// inputs whose steps can be reused.
+type pipelineRun struct {
+	m    *pipelineManifest
+	prev *pipelineManifest
+	path string
+}
+
+// loadPipelineManifest reads the manifest at path, or returns nil if there
+// is none. A manifest from a run with other inputs is an error rather than
+// being overwritten.
+func loadPipelineManifest(path string, m *pipelineManifest) (*pipelineManifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var prev pipelineManifest
+	if err := json.Unmarshal(data, &prev); err != nil {
+		return nil, fmt.Errorf("read manifest %s: %w (delete it or use --restart)", path, err)
+	}
+	if prev.Pipeline != m.Pipeline || prev.Topic != m.Topic || prev.Keyword != m.Keyword ||
+		prev.Engine != m.Engine || prev.Language != m.Language {
+		return nil, fmt.Errorf("%s is the manifest of another run (topic %q, engine %s, language %s); use --restart to replace it",
+			path, prev.Topic, prev.Engine, prev.Language)
+	}
+	return &prev, nil
+}
+
+// previous returns the earlier run's step called name, if any.
+func (r *pipelineRun) previous(name string) *pipelineStep {
+	if r.prev == nil {
+		return nil
+	}
+	for i := range r.prev.Steps {
+		if r.prev.Steps[i].Name == name {
+			return &r.prev.Steps[i]
+		}
+	}
+	return nil
+}
+
+func (r *pipelineRun) save() error {
+	data, err := json.MarshalIndent(r.m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := output.WriteFileAtomic(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("save manifest: %w", err)
+	}
+	return nil
+}
+
+func runPipelineBlog(cmd *cobra.Command, args []string) error {
+	if pipelineIdeas < 1 || pipelineIdeas > writesonic.MaxCopies {
+		return fmt.Errorf("--ideas must be between 1 and %d", writesonic.MaxCopies)
+	}
+	if outputFlag != "" && outputDirFlag != "" {
+		return fmt.Errorf("use either --output or --output-dir, not both")
+	}
+	policy, err := output.ParseExistsPolicy(onExistsFlag)
+	if err != nil {
+		return fmt.Errorf("--on-exists: %w", err)
+	}
+	intro, err := textInput{flag: "intro", value: pipelineIntro, file: pipelineIntroFile}.read()
+	if err != nil {
+		return err
+	}
+
+	docPath := func(title string) string {
+		if outputFlag != "" {
+			return outputFlag
+		}
+		return filepath.Join(outputDirFlag, postSlug(title)+".md")
+	}
+	// The manifest must be found before any idea is generated, so it's named
+	// after what is known up front.
+	name := firstNonEmpty(pipelineTitle, pipelineTopic)
+	manifestPath := strings.TrimSuffix(docPath(name), filepath.Ext(docPath(name))) + ".manifest.json"
+	if policy == output.Skip && (outputFlag != "" || pipelineTitle != "") && fileExists(docPath(name)) {
+		fmt.Fprintf(os.Stderr, "skipped %s (exists)\n", docPath(name))
+		return nil
+	}
+
+	ctx := cmd.Context()
+	m := &pipelineManifest{
+		Pipeline: "blog",
+		Topic:    pipelineTopic,
+		Keyword:  pipelineKeyword,
+		Engine:   engineFlag,
+		Language: langFlag,
+		Created:  time.Now().UTC(),
+	}
+	if pipelineRestart {
+		if err := os.Remove(manifestPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	prev, err := loadPipelineManifest(manifestPath, m)
+	if err != nil {
+		return err
+	}
+	if prev != nil {
+		m.Created = prev.Created
+	}
+	run := &pipelineRun{m: m, prev: prev, path: manifestPath}
+	one := writesonic.WithParams(writesonic.Params{Engine: engineFlag, Language: langFlag, Copies: 1})
+
+	// 1–2. Ideas and pick.
+	title := pipelineTitle
+	if title == "" {
+		req := writesonic.BlogIdeasRequest{Topic: pipelineTopic, PrimaryKeyword: pipelineKeyword}
+		results, err := run.step("ideas", "/blog-ideas", req, func() ([]writesonic.Result, error) {
+			return client.BlogIdeas(ctx, req, writesonic.WithParams(writesonic.Params{
+				Engine: engineFlag, Language: langFlag, Copies: pipelineIdeas,
+			}))
+		})
+		if err != nil {
+			return err
+		}
+		ideas := listItems(results)
+		if len(ideas) == 0 {
+			return fmt.Errorf("ideas: the API returned no ideas")
+		}
+		// A resumed run keeps the idea picked the first time.
+		if p := run.previous("ideas"); p != nil && pipelineIdea == 0 && slices.Contains(ideas, p.Selected) &&
+			m.Steps[len(m.Steps)-1].Reused {
+			title = p.Selected
+		} else if title, err = pickIdea(ideas); err != nil {
+			return err
+		}
+		m.Steps[len(m.Steps)-1].Selected = title
+		if policy == output.Skip && fileExists(docPath(title)) {
+			fmt.Fprintf(os.Stderr, "skipped %s (exists)\n", docPath(title))
+			return nil
+		}
+	} else {
+		m.Steps = append(m.Steps, pipelineStep{Name: "ideas", Selected: title})
+	}
+	m.Title = title
+
+	// 3. Outline.
+	var sections []string
+	if pipelineSections != "" {
+		for _, s := range strings.Split(pipelineSections, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				sections = append(sections, s)
+			}
+		}
+		m.Steps = append(m.Steps, pipelineStep{Name: "outline", Results: sections})
+	} else {
+		req := writesonic.BulletAnswersRequest{
+			Question: fmt.Sprintf("What are the main sections of a blog post titled %q? Answer with at most %d short section titles.", title, maxGeneratedSections),
+		}
+		results, err := run.step("outline", "/bulletpoint-answers", req, func() ([]writesonic.Result, error) {
+			return client.BulletAnswers(ctx, req, one)
+		})
+		if err != nil {
+			return err
+		}
+		sections = listItems(results[:1])
+		if len(sections) > maxGeneratedSections {
+			sections = sections[:maxGeneratedSections]
+		}
+		if len(sections) == 0 {
+			return fmt.Errorf("outline: no section titles in the answer; pass --sections")
+		}
+		m.Steps[len(m.Steps)-1].Selected = strings.Join(sections, ", ")
+	}
+
+	// 4. Intro.
+	if intro != "" {
+		m.Steps = append(m.Steps, pipelineStep{Name: "intro", Selected: intro})
+	} else {
+		req := writesonic.ParagraphRequest{
+			Topic:        title,
+			Instructions: "Write the introduction of a blog post with this title.",
+		}
+		results, err := run.step("intro", "/paragraph-writer", req, func() ([]writesonic.Result, error) {
+			return client.Paragraph(ctx, req, one)
+		})
+		if err != nil {
+			return err
+		}
+		intro = strings.TrimSpace(results[0].Text)
+	}
+
+	// 5. Article.
+	articleReq := writesonic.ArticleRequest{Title: title, Intro: intro, Sections: sections}
+	results, err := run.step("article", "/ai-article-writer-v3", articleReq, func() ([]writesonic.Result, error) {
+		return client.Article(ctx, articleReq, one)
+	})
+	if err != nil {
+		return err
+	}
+	article := results[0].Text
+
+	// 6. Meta.
+	summary := site.Excerpt(article, 1000)
+	if summary == "" {
+		summary = intro
+	}
+	metaReq := writesonic.MetaBlogRequest{Title: title, Description: summary}
+	results, err = run.step("meta", "/meta-blog", metaReq, func() ([]writesonic.Result, error) {
+		return client.MetaBlog(ctx, metaReq, one)
+	})
+	if err != nil {
+		return err
+	}
+	metaTitle, metaDescription := site.ParseMeta(results[0].Text)
+
+	// 7. Conclusion.
+	conclusionReq := writesonic.ConclusionRequest{Topic: title}
+	results, err = run.step("conclusion", "/conclusion-writer", conclusionReq, func() ([]writesonic.Result, error) {
+		return client.Conclusion(ctx, conclusionReq, one)
+	})
+	if err != nil {
+		return err
+	}
+	conclusion := strings.TrimSpace(results[0].Text)
+
+	// Assemble and write.
+	page := site.Page{
+		Title:       title,
+		Slug:        postSlug(title),
+		Date:        m.Created,
+		Language:    langFlag,
+		Description: metaDescription,
+		Body:        strings.TrimSpace(article) + "\n\n## Conclusion\n\n" + conclusion,
+	}
+	if metaTitle != title {
+		page.MetaTitle = metaTitle
+	}
+	if pipelineKeyword != "" {
+		page.Tags = []string{pipelineKeyword}
+	}
+	doc, err := pipelineDocument.Render(page)
+	if err != nil {
+		return err
+	}
+
+	path := docPath(title)
+	written, err := output.WriteFile(path, doc, policy)
+	if err != nil {
+		return err
+	}
+	if written == "" {
+		// The kept document isn't this run's, so neither is its manifest.
+		fmt.Fprintf(os.Stderr, "skipped %s (exists)\n", path)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", written)
+	m.Document = written
+	if err := run.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", manifestPath)
+
+	if output.IsJSON(jsonFlag, prettyFlag) {
+		return output.PrintJSON(m, prettyFlag)
+	}
+	output.PrintKeyValue([][]string{
+		{"Title", title},
+		{"Sections", strings.Join(sections, ", ")},
+		{"Document", written},
+		{"Manifest", manifestPath},
+	})
+	return nil
+}
+
+// pipelineDocument renders the assembled post: Markdown with YAML front
+// matter, not tied to a site generator's layout.
+var pipelineDocument = site.Generator{Name: "markdown", Ext: "md", DateKey: "date", DateLayout: "2006-01-02"}
+
+// step runs one API step, reporting it on stderr and recording it in the
+// manifest, which is saved at once so a later failure doesn't lose it. A step
+// the earlier run already made with the same request is reused instead.
+func (r *pipelineRun) step(name, endpoint string, req interface{},
+	call func() ([]writesonic.Result, error)) ([]writesonic.Result, error) {
+	step := pipelineStep{Name: name, Endpoint: endpoint, Request: req}
+	if p := r.previous(name); p != nil && p.Endpoint == endpoint && len(p.Results) > 0 && sameJSON(p.Request, req) {
+		fmt.Fprintf(os.Stderr, "%s: %s (reused)\n", name, endpoint)
+		step.Results, step.Reused = p.Results, true
+		r.m.Steps = append(r.m.Steps, step)
+		results := make([]writesonic.Result, len(p.Results))
+		for i, text := range p.Results {
+			results[i] = writesonic.Result{Text: text}
+		}
+		return results, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: %s\n", name, endpoint)
+	results, err := call()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("%s: the API returned no results", name)
+	}
+	for _, res := range results {
+		step.Results = append(step.Results, res.Text)
+	}
+	r.m.Steps = append(r.m.Steps, step)
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// sameJSON reports whether a and b encode to the same JSON, e.g. a request
+// read back from a manifest and the one about to be sent.
+func sameJSON(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	var va, vb interface{}
+	if json.Unmarshal(ja, &va) != nil || json.Unmarshal(jb, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+var listMarker = regexp.MustCompile(`^\s*(?:[-*+•]|\d+[.)])\s+`)
+
+// listItems splits results into one item per non-empty line, without list
+// markers, bold markers or duplicates. A result made of a single line is one
+// item; lines that don't look like list items are skipped when others do.
+func listItems(results []writesonic.Result) []string {
+	var items []string
+	seen := map[string]bool{}
+	for _, r := range results {
+		lines := strings.Split(strings.TrimSpace(r.Text), "\n")
+		marked := false
+		for _, line := range lines {
+			if listMarker.MatchString(line) {
+				marked = true
+				break
+			}
+		}
+		for _, line := range lines {
+			if marked && !listMarker.MatchString(line) {
+				continue
+			}
+			item := strings.TrimSpace(listMarker.ReplaceAllString(line, ""))
+			item = strings.Trim(strings.ReplaceAll(item, "**", ""), `"`)
+			if item == "" || seen[item] {
+				continue
+			}
+			seen[item] = true
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// pickIdea returns idea --idea, asks on a terminal, or takes the first.
+func pickIdea(ideas []string) (string, error) {
+	if pipelineIdea != 0 {
+		if pipelineIdea < 1 || pipelineIdea > len(ideas) {
+			return "", fmt.Errorf("--idea %d: there are %d ideas", pipelineIdea, len(ideas))
+		}
+		return ideas[pipelineIdea-1], nil
+	}
+	if len(ideas) == 1 || !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stderr.Fd()) {
+		return ideas[0], nil
+	}
+	return promptIdea(os.Stdin, os.Stderr, ideas)
+}
+
+// promptIdea lists ideas on out and reads a choice from in until it gets a
+// valid one; an empty answer takes the first.
+func promptIdea(in io.Reader, out io.Writer, ideas []string) (string, error) {
+	for i, idea := range ideas {
+		fmt.Fprintf(out, "  %d. %s\n", i+1, idea)
+	}
+	r := bufio.NewReader(in)
+	for {
+		fmt.Fprintf(out, "Pick an idea [1-%d] (default 1): ", len(ideas))
+		answer, err := r.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			if err != nil {
+				return "", fmt.Errorf("no idea picked")
+			}
+			return ideas[0], nil
+		}
+		if n, convErr := strconv.Atoi(answer); convErr == nil && n >= 1 && n <= len(ideas) {
+			return ideas[n-1], nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("no idea picked")
+		}
+	}
+}
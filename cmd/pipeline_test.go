@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+func TestListItems(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		results []string
+		want    []string
+	}{
+		{"numbered list with preamble", []string{"Here are some ideas:\n1. First idea\n2) **Second** idea\n\n3. \"Third\""},
+			[]string{"First idea", "Second idea", "Third"}},
+		{"bullets", []string{"- One\n* Two\n+ Three\n• Four"}, []string{"One", "Two", "Three", "Four"}},
+		{"plain lines", []string{"Alpha\n\nBeta\n"}, []string{"Alpha", "Beta"}},
+		{"one per copy, duplicates dropped", []string{"Same idea", "Other idea", "Same idea"}, []string{"Same idea", "Other idea"}},
+		{"empty", []string{"", "  \n "}, nil},
+	} {
+		results := make([]writesonic.Result, len(tc.results))
+		for i, r := range tc.results {
+			results[i] = writesonic.Result{Text: r}
+		}
+		if got := listItems(results); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestPickIdeaFlag(t *testing.T) {
+	defer func(n int) { pipelineIdea = n }(pipelineIdea)
+	ideas := []string{"a", "b", "c"}
+
+	pipelineIdea = 2
+	if got, err := pickIdea(ideas); err != nil || got != "b" {
+		t.Errorf("--idea 2: got %q, %v", got, err)
+	}
+	pipelineIdea = 4
+	if _, err := pickIdea(ideas); err == nil || !strings.Contains(err.Error(), "there are 3 ideas") {
+		t.Errorf("--idea 4: err = %v", err)
+	}
+}
+
+func TestPromptIdea(t *testing.T) {
+	ideas := []string{"a", "b", "c"}
+	for _, tc := range []struct {
+		input, want string
+		fails       bool
+	}{
+		{input: "2\n", want: "b"},
+		{input: "\n", want: "a"},
+		{input: "9\nx\n3\n", want: "c"}, // invalid answers ask again
+		{input: " 1 ", want: "a"},       // last line without a newline
+		{input: "", fails: true},
+		{input: "7", fails: true},
+	} {
+		got, err := promptIdea(strings.NewReader(tc.input), io.Discard, ideas)
+		if tc.fails {
+			if err == nil {
+				t.Errorf("input %q: got %q, want an error", tc.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("input %q: got %q, %v; want %q", tc.input, got, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package cmd
+
+// profile.go implements "auth profile": named configuration profiles, each
+// with its own API key and defaults, for working across several accounts.
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/internal/config"
+	"github.com/the20100/writesonic-cli/internal/output"
+)
+
+var (
+	profileEngine   string
+	profileLanguage string
+	profileCopies   int
+	profileBaseURL  string
+	profileUse      bool
+)
+
+var authProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named profiles for multiple accounts or workspaces",
+	Long: `Profiles keep separate API keys and defaults side by side in the config file.
+Select one per command with --profile <name> or WRITESONIC_PROFILE=<name>, or make
+it the default with "writesonic auth profile use <name>". The top-level settings
+form the implicit "default" profile.`,
+}
+
+var authProfileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Create or update a profile",
+	Long: `Create a profile, or update its defaults.
+
+The API key isn't taken here, since arguments end up in shell history; set it
+afterwards with "writesonic auth set-key --profile <name>", which prompts.`,
+	Example: `  writesonic auth profile add acme --engine premium --language fr
+  writesonic auth set-key --profile acme
+  writesonic auth profile add staging --base-url https://staging.example.com/v2/business/content --use`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAuthProfileAdd,
+}
+
+var authProfileUseCmd = &cobra.Command{
+	Use:     "use <name>",
+	Short:   `Make a profile active by default ("default" for top-level settings)`,
+	Example: `  writesonic auth profile use acme`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runAuthProfileUse,
+}
+
+var authProfileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Args:  cobra.NoArgs,
+	RunE:  runAuthProfileList,
+}
+
+var authProfileRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Delete a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAuthProfileRemove,
+}
+
+var authProfileRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a profile",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runAuthProfileRename,
+}
+
+func init() {
+	authProfileAddCmd.Flags().StringVar(&profileEngine, "engine", "", "Default engine (economy, average, good, premium)")
+	authProfileAddCmd.Flags().StringVar(&profileLanguage, "language", "", "Default language code (e.g. en, fr, de)")
+	authProfileAddCmd.Flags().IntVar(&profileCopies, "copies", 0, "Default number of copies (1-5)")
+	authProfileAddCmd.Flags().StringVar(&profileBaseURL, "base-url", "", "API base URL for this profile")
+	authProfileAddCmd.Flags().BoolVar(&profileUse, "use", false, "Also make this the active profile")
+
+	authProfileCmd.AddCommand(authProfileAddCmd, authProfileUseCmd, authProfileListCmd,
+		authProfileRemoveCmd, authProfileRenameCmd)
+	authCmd.AddCommand(authProfileCmd)
+}
+
+var profileNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func validateProfileName(name string) error {
+	if config.IsDefaultProfile(name) {
+		return fmt.Errorf("%q is reserved for the top-level settings", config.DefaultProfile)
+	}
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, '.', '_' or '-')", name)
+	}
+	return nil
+}
+
+// loadConfigForEdit loads the config file for a change that will be saved
+// back. A file that can't be read or parsed is an error rather than an empty
+// config, since saving over it would wipe it.
+func loadConfigForEdit() (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	return cfg, nil
+}
+
+func runAuthProfileAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	cfg, err := loadConfigForEdit()
+	if err != nil {
+		return err
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*config.Profile{}
+	}
+	p, exists := cfg.Profiles[name]
+	if !exists || p == nil {
+		p = &config.Profile{}
+		cfg.Profiles[name] = p
+	}
+	if _, err := setProfileDefaults(cmd, cfg, name, map[string]string{
+		"engine":   "default_engine",
+		"language": "default_language",
+		"copies":   "default_copies",
+		"base-url": "base_url",
+	}); err != nil {
+		return err
+	}
+	if profileUse {
+		cfg.ActiveProfile = name
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	verb := "created"
+	if exists {
+		verb = "updated"
+	}
+	fmt.Printf("Profile %q %s.\n", name, verb)
+	if !exists {
+		fmt.Printf("Set its key with: writesonic auth set-key --profile %s\n", name)
+	}
+	if !profileUse {
+		fmt.Printf("Use it with --profile %s, or make it the default: writesonic auth profile use %s\n", name, name)
+	}
+	return nil
+}
+
+func runAuthProfileUse(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	cfg, err := loadConfigForEdit()
+	if err != nil {
+		return err
+	}
+	if _, err := cfg.LookupProfile(name); err != nil {
+		return err
+	}
+	if config.IsDefaultProfile(name) {
+		cfg.ActiveProfile = ""
+	} else {
+		cfg.ActiveProfile = name
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	fmt.Printf("Active profile is now %q.\n", name)
+	return nil
+}
+
+func runAuthProfileList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigForEdit()
+	if err != nil {
+		return err
+	}
+	active, _ := profileName(cfg)
+	store, err := credentialStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	names := []string{config.DefaultProfile}
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+
+	type profileRow struct {
+		Name     string `json:"name"`
+		Active   bool   `json:"active"`
+		HasKey   bool   `json:"has_key"`
+		Engine   string `json:"engine,omitempty"`
+		Language string `json:"language,omitempty"`
+		Copies   int    `json:"copies,omitempty"`
+		BaseURL  string `json:"base_url,omitempty"`
+	}
+	var list []profileRow
+	keys := map[string]string{}
+	for _, name := range names {
+		p, err := cfg.LookupProfile(name)
+		if err != nil {
+			continue
+		}
+		key := p.APIKey
+		if key == "" {
+			key, err = store.Get(name)
+			if err != nil && !errors.Is(err, config.ErrNoCredential) {
+				return err
+			}
+		}
+		keys[name] = key
+		list = append(list, profileRow{
+			Name: name, Active: name == active || (config.IsDefaultProfile(name) && config.IsDefaultProfile(active)),
+			HasKey: key != "", Engine: p.DefaultEngine, Language: p.DefaultLanguage,
+			Copies: p.DefaultCopies, BaseURL: p.BaseURL,
+		})
+	}
+
+	if output.IsJSON(jsonFlag, prettyFlag) {
+		return output.PrintJSON(list, prettyFlag)
+	}
+	rows := make([][]string, len(list))
+	for i, r := range list {
+		marker, key, copies := "", "-", "-"
+		if r.Active {
+			marker = "*"
+		}
+		if r.HasKey {
+			key = maskKey(keys[r.Name])
+		}
+		if r.Copies > 0 {
+			copies = fmt.Sprintf("%d", r.Copies)
+		}
+		rows[i] = []string{marker, r.Name, key, dash(r.Engine), dash(r.Language), copies, dash(r.BaseURL)}
+	}
+	output.PrintTable([]string{"", "NAME", "API KEY", "ENGINE", "LANGUAGE", "COPIES", "BASE URL"}, rows)
+	return nil
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func runAuthProfileRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	cfg, err := loadConfigForEdit()
+	if err != nil {
+		return err
+	}
+	if _, err := cfg.LookupProfile(name); err != nil {
+		return err
+	}
+	if err := deleteAPIKey(cfg, name); err != nil {
+		return err
+	}
+	delete(cfg.Profiles, name)
+	if cfg.ActiveProfile == name {
+		cfg.ActiveProfile = ""
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	fmt.Printf("Profile %q removed.\n", name)
+	return nil
+}
+
+func runAuthProfileRename(cmd *cobra.Command, args []string) error {
+	from, to := args[0], args[1]
+	if err := validateProfileName(from); err != nil {
+		return err
+	}
+	if err := validateProfileName(to); err != nil {
+		return err
+	}
+	cfg, err := loadConfigForEdit()
+	if err != nil {
+		return err
+	}
+	p, err := cfg.LookupProfile(from)
+	if err != nil {
+		return err
+	}
+	if _, exists := cfg.Profiles[to]; exists {
+		return fmt.Errorf("profile %q already exists", to)
+	}
+	if err := moveAPIKey(cfg, from, to); err != nil {
+		return err
+	}
+	cfg.Profiles[to] = p
+	delete(cfg.Profiles, from)
+	if cfg.ActiveProfile == from {
+		cfg.ActiveProfile = to
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	fmt.Printf("Profile %q renamed to %q.\n", from, to)
+	return nil
+}
+
+// moveAPIKey moves a key kept outside the config file from one profile name
+// to another. Keys in config.json move with the profile itself.
+func moveAPIKey(cfg *config.Config, from, to string) error {
+	store, err := credentialStore(cfg)
+	if err != nil || store.Name() == config.StoreConfig {
+		return err
+	}
+	key, err := store.Get(from)
+	if errors.Is(err, config.ErrNoCredential) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := store.Set(to, key); err != nil {
+		return err
+	}
+	return store.Delete(from)
+}
@@ -1,7 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/internal/api"
+	"github.com/the20100/writesonic-cli/internal/chunk"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
 )
 
 // rewrite.go groups content transformation commands: rephrase, shorten, tone-changer, rewrite-with-keywords
@@ -15,6 +25,15 @@ var (
 	toneTone         string
 	kwContent        string
 	kwKeywords       string
+
+	rephraseContentFile string
+	shortenContentFile  string
+	toneContentFile     string
+	kwContentFile       string
+
+	rephraseDocument bool
+	shortenDocument  bool
+	chunkSize        int
 )
 
 var rewriteCmd = &cobra.Command{
@@ -23,90 +42,237 @@ var rewriteCmd = &cobra.Command{
 }
 
 var rewriteRephraseCmd = &cobra.Command{
-	Use:   "rephrase",
+	Use:   "rephrase [file...]",
 	Short: "Rephrase content in a different style",
 	Example: `  writesonic rewrite rephrase --content "The quick brown fox jumps over the lazy dog."
-  writesonic rewrite rephrase --content "Our product is amazing." --tone "formal"`,
+  writesonic rewrite rephrase --content "Our product is amazing." --tone "formal"
+  writesonic rewrite rephrase --content-file intro.txt
+  writesonic rewrite rephrase --document post.md --concurrency 4 > post.rephrased.md`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runRephrase,
 }
 
 var rewriteShortenCmd = &cobra.Command{
-	Use:   "shorten",
+	Use:   "shorten [file...]",
 	Short: "Shorten content while keeping the message",
 	Example: `  writesonic rewrite shorten --content "Our product is the most amazing and revolutionary tool on the market today."
-  writesonic rewrite shorten --content "Long paragraph here..." --tone "casual"`,
+  writesonic rewrite shorten --content "Long paragraph here..." --tone "casual"
+  pbpaste | writesonic rewrite shorten --content -
+  writesonic rewrite shorten --document long-post.md`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runShorten,
 }
 
 var rewriteToneCmd = &cobra.Command{
-	Use:   "tone",
+	Use:   "tone [file...]",
 	Short: "Change the tone of existing content",
 	Example: `  writesonic rewrite tone --content "Hey there! Check out our new product!" --tone "formal"
-  writesonic rewrite tone --content "Our quarterly results show..." --tone "casual"`,
+  writesonic rewrite tone --content "Our quarterly results show..." --tone "casual"
+  cat draft.md | writesonic rewrite tone --tone formal`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runToneChanger,
 }
 
 var rewriteKeywordsCmd = &cobra.Command{
-	Use:   "keywords",
+	Use:   "keywords [file...]",
 	Short: "Rewrite content with target SEO keywords",
 	Example: `  writesonic rewrite keywords --content "We sell software." --keywords "project management, team collaboration"
-  writesonic rewrite keywords --content "Article text here" --keywords "AI, machine learning, automation"`,
+  writesonic rewrite keywords --content "Article text here" --keywords "AI, machine learning, automation"
+  writesonic rewrite keywords about.md --keywords "SaaS, automation"`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runRewriteKeywords,
 }
 
 func init() {
-	rewriteRephraseCmd.Flags().StringVar(&rephraseContent, "content", "", "Content to rephrase (required, 20-1000 chars)")
+	rewriteRephraseCmd.Flags().StringVar(&rephraseContent, "content", "", "Content to rephrase (required, 20-1000 chars; - for stdin)")
+	rewriteRephraseCmd.Flags().StringVar(&rephraseContentFile, "content-file", "", "Read content from a file")
 	rewriteRephraseCmd.Flags().StringVar(&rephraseTone, "tone", "", "Desired tone of voice (optional)")
-	rewriteRephraseCmd.MarkFlagRequired("content")
+	rewriteRephraseCmd.Flags().BoolVar(&rephraseDocument, "document", false, "Rewrite a long Markdown/text document chunk by chunk")
+	rewriteRephraseCmd.Flags().IntVar(&chunkSize, "chunk-size", 1000, "Maximum characters per chunk in --document mode")
 
-	rewriteShortenCmd.Flags().StringVar(&shortenContent, "content", "", "Content to shorten (required, 20-1000 chars)")
+	rewriteShortenCmd.Flags().StringVar(&shortenContent, "content", "", "Content to shorten (required, 20-1000 chars; - for stdin)")
+	rewriteShortenCmd.Flags().StringVar(&shortenContentFile, "content-file", "", "Read content from a file")
 	rewriteShortenCmd.Flags().StringVar(&shortenTone, "tone", "", "Desired tone of voice (optional)")
-	rewriteShortenCmd.MarkFlagRequired("content")
+	rewriteShortenCmd.Flags().BoolVar(&shortenDocument, "document", false, "Rewrite a long Markdown/text document chunk by chunk")
+	rewriteShortenCmd.Flags().IntVar(&chunkSize, "chunk-size", 1000, "Maximum characters per chunk in --document mode")
 
-	rewriteToneCmd.Flags().StringVar(&toneContent, "content", "", "Content to transform (required)")
+	rewriteToneCmd.Flags().StringVar(&toneContent, "content", "", "Content to transform (required; - for stdin)")
+	rewriteToneCmd.Flags().StringVar(&toneContentFile, "content-file", "", "Read content from a file")
 	rewriteToneCmd.Flags().StringVar(&toneTone, "tone", "", "Target tone (e.g. formal, casual, professional)")
-	rewriteToneCmd.MarkFlagRequired("content")
 	rewriteToneCmd.MarkFlagRequired("tone")
 
-	rewriteKeywordsCmd.Flags().StringVar(&kwContent, "content", "", "Content to rewrite (required)")
+	rewriteKeywordsCmd.Flags().StringVar(&kwContent, "content", "", "Content to rewrite (required; - for stdin)")
+	rewriteKeywordsCmd.Flags().StringVar(&kwContentFile, "content-file", "", "Read content from a file")
 	rewriteKeywordsCmd.Flags().StringVar(&kwKeywords, "keywords", "", "Comma-separated target keywords (required)")
-	rewriteKeywordsCmd.MarkFlagRequired("content")
 	rewriteKeywordsCmd.MarkFlagRequired("keywords")
 
+	addPickFlag(rewriteRephraseCmd, rewriteShortenCmd, rewriteToneCmd, rewriteKeywordsCmd)
+	addOutputFlags(rewriteRephraseCmd, rewriteShortenCmd, rewriteToneCmd, rewriteKeywordsCmd)
 	rewriteCmd.AddCommand(rewriteRephraseCmd, rewriteShortenCmd, rewriteToneCmd, rewriteKeywordsCmd)
 	rootCmd.AddCommand(rewriteCmd)
 }
 
+// rewriteContent resolves --content for a rewrite command from the flag,
+// stdin, --content-file, positional files, or piped stdin.
+func rewriteContent(value, file string, args []string) (string, error) {
+	return textInput{flag: "content", value: value, file: file, args: args, implicitStdin: true}.require()
+}
+
 func runRephrase(cmd *cobra.Command, args []string) error {
-	body := map[string]interface{}{
-		"content_to_rephrase": rephraseContent,
+	content, err := rewriteContent(rephraseContent, rephraseContentFile, args)
+	if err != nil {
+		return err
+	}
+	rephrase := func(text string) textCall {
+		return call(client.Rephrase, writesonic.RephraseRequest{Content: text, Tone: rephraseTone})
 	}
-	if rephraseTone != "" {
-		body["tone_of_voice"] = rephraseTone
+	if rephraseDocument {
+		return rewriteDocument(cmd.Context(), content, rephrase)
 	}
-	return postAndPrint("/content-rephrase", body)
+	return printCall(cmd.Context(), rephrase(content))
 }
 
 func runShorten(cmd *cobra.Command, args []string) error {
-	body := map[string]interface{}{
-		"content_to_shorten": shortenContent,
+	content, err := rewriteContent(shortenContent, shortenContentFile, args)
+	if err != nil {
+		return err
+	}
+	shorten := func(text string) textCall {
+		return call(client.Shorten, writesonic.ShortenRequest{Content: text, Tone: shortenTone})
 	}
-	if shortenTone != "" {
-		body["tone_of_voice"] = shortenTone
+	if shortenDocument {
+		return rewriteDocument(cmd.Context(), content, shorten)
 	}
-	return postAndPrint("/content-shorten", body)
+	return printCall(cmd.Context(), shorten(content))
 }
 
 func runToneChanger(cmd *cobra.Command, args []string) error {
-	return postAndPrint("/tone-changer", map[string]interface{}{
-		"content_to_change": toneContent,
-		"tone":              toneTone,
-	})
+	content, err := rewriteContent(toneContent, toneContentFile, args)
+	if err != nil {
+		return err
+	}
+	return printCall(cmd.Context(), call(client.ChangeTone, writesonic.ChangeToneRequest{
+		Content: content,
+		Tone:    toneTone,
+	}))
 }
 
 func runRewriteKeywords(cmd *cobra.Command, args []string) error {
-	return postAndPrint("/rewrite-with-keywords", map[string]interface{}{
-		"content":  kwContent,
-		"keywords": kwKeywords,
+	content, err := rewriteContent(kwContent, kwContentFile, args)
+	if err != nil {
+		return err
+	}
+	return printCall(cmd.Context(), call(client.RewriteWithKeywords, writesonic.KeywordsRequest{
+		Content:  content,
+		Keywords: kwKeywords,
+	}))
+}
+
+// rewriteDocument splits content into API-sized prose chunks, rewrites each
+// with rewrite through the scheduler, and reassembles the document in order.
+// Structural Markdown is passed through untouched. With --copies N, variant i
+// is built from the i-th result of every chunk. A variant spans many calls,
+// so --pick can't regenerate one.
+//
+// A chunk that fails keeps its original text and the rest of the document is
+// still rewritten and printed, so the chunks already billed aren't lost; the
+// failed chunks are then reported as an error. Auth and quota errors stop the
+// run early, since every remaining chunk would fail the same way.
+func rewriteDocument(ctx context.Context, content string, rewrite func(text string) textCall) error {
+	if chunkSize < 20 {
+		return fmt.Errorf("--chunk-size must be at least 20")
+	}
+	pieces := chunk.Split(content, chunk.Options{MaxLen: chunkSize, MinLen: 20})
+
+	var jobs []api.Job
+	var idx []int // piece index for each job
+	for i, p := range pieces {
+		if !p.Rewrite {
+			continue
+		}
+		text := p.Text
+		idx = append(idx, i)
+		jobs = append(jobs, api.Job{
+			Key: engineFlag,
+			Do: func(ctx context.Context) (interface{}, error) {
+				return rewrite(text)(ctx, copiesFlag)
+			},
+		})
+	}
+
+	variants := make([][]string, copiesFlag)
+	for v := range variants {
+		variants[v] = make([]string, len(pieces))
+	}
+	var failed []int // chunk numbers, 1-based
+	var firstErr error
+	rewritten := 0
+	err := newScheduler().Run(ctx, jobs, func(r api.JobResult) error {
+		n := r.Index + 1
+		if r.Err == nil && len(r.Value.([]writesonic.Result)) == 0 {
+			r.Err = fmt.Errorf("empty response")
+		}
+		if r.Err != nil {
+			if ctx.Err() != nil || errors.Is(r.Err, context.Canceled) {
+				return nil // cut short by an interrupt or early stop; reported by Run
+			}
+			failed = append(failed, n)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("chunk %d: %w", n, r.Err)
+			}
+			fmt.Fprintf(os.Stderr, "chunk %d of %d: keeping original text: %v\n", n, len(jobs), r.Err)
+			if class := writesonic.Classify(r.Err); class == writesonic.ClassAuth || class == writesonic.ClassQuota {
+				return fmt.Errorf("stopping after %s error on chunk %d: %w", class, n, r.Err)
+			}
+			return nil
+		}
+		results := r.Value.([]writesonic.Result)
+		p := pieces[idx[r.Index]]
+		for v := range variants {
+			res := results[0]
+			if v < len(results) {
+				res = results[v]
+			}
+			text, err := p.Restore(res.Text)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "chunk %d: keeping original text: %v\n", n, err)
+				text = p.Original()
+			}
+			variants[v][idx[r.Index]] = text
+		}
+		rewritten++
+		return nil
 	})
+	if rewritten == 0 {
+		if err != nil {
+			return err
+		}
+		if firstErr != nil {
+			return firstErr
+		}
+	}
+
+	results := make([]writesonic.Result, len(variants))
+	for v, texts := range variants {
+		results[v] = writesonic.Result{Text: chunk.Join(pieces, texts)}
+	}
+	if perr := printResults(results, nil); perr != nil {
+		return perr
+	}
+	if err != nil {
+		return fmt.Errorf("%d of %d chunks rewritten before the run stopped: %w", rewritten, len(jobs), err)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d chunks failed and were kept as they were (chunks %s); first failure, %w",
+			len(failed), len(jobs), joinInts(failed), firstErr)
+	}
+	return nil
+}
+
+func joinInts(ns []int) string {
+	s := make([]string, len(ns))
+	for i, n := range ns {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ", ")
 }
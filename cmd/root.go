@@ -1,13 +1,21 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"github.com/the20100/writesonic-cli/internal/api"
 	"github.com/the20100/writesonic-cli/internal/config"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
 )
 
 var (
@@ -17,7 +25,32 @@ var (
 	langFlag   string
 	copiesFlag int
 
-	client *api.Client
+	retriesFlag       int
+	retryDelayFlag    time.Duration
+	retryMaxDelayFlag time.Duration
+	timeoutFlag       time.Duration
+
+	baseURLFlag    string
+	proxyFlag      string
+	caCertFlag     string
+	clientCertFlag string
+	clientKeyFlag  string
+
+	concurrencyFlag int
+	rateLimitFlag   map[string]int
+
+	noHistoryFlag bool
+	profileFlag   string
+
+	cacheFlag    bool
+	noCacheFlag  bool
+	cacheTTLFlag time.Duration
+
+	recordFlag       string
+	replayFlag       string
+	replayStrictFlag bool
+
+	client *writesonic.Client
 	cfg    *config.Config
 )
 
@@ -28,15 +61,28 @@ var rootCmd = &cobra.Command{
 Generate blog ideas, articles, landing pages, and more from your terminal.
 
 Authentication:
-  Set your API key with:  writesonic auth set-key <your-key>
+  Set your API key with:  writesonic auth set-key   (prompts without echoing)
+  Or from a pipe:         pass show writesonic | writesonic auth set-key
   Or via environment var: WRITESONIC_API_KEY=<your-key> (or aliases: WRITESONIC_KEY, WRITESONIC_API, ...)`,
-	SilenceUsage: true,
+	SilenceUsage:  true,
+	SilenceErrors: true,
 }
 
-// Execute runs the root command.
+// Execute runs the root command. SIGINT and SIGTERM cancel the command
+// context so in-flight API requests are aborted cleanly.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cmd, err := rootCmd.ExecuteContextC(ctx); err != nil {
+		stop()
+		if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+			fmt.Fprintln(os.Stderr, "Interrupted: request cancelled.")
+			os.Exit(ExitInterrupted)
+		}
+		err = explainFlagError(commandName(cmd), err)
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -46,50 +92,258 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&engineFlag, "engine", "", "AI engine: economy, average, good, premium (default from config)")
 	rootCmd.PersistentFlags().StringVar(&langFlag, "lang", "", "Language code (e.g. en, fr, de) (default from config)")
 	rootCmd.PersistentFlags().IntVar(&copiesFlag, "copies", 0, "Number of copies to generate (1-5, default from config)")
+	rootCmd.PersistentFlags().IntVar(&concurrencyFlag, "concurrency", 0, "Parallel requests for bulk commands (default from config, else 1)")
+	rootCmd.PersistentFlags().StringToIntVar(&rateLimitFlag, "rate-limit", nil, "Requests per minute by engine, e.g. premium=30,default=120")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Configuration profile to use (env: WRITESONIC_PROFILE)")
+	rootCmd.PersistentFlags().BoolVar(&cacheFlag, "cache", false, "Serve identical requests from the local response cache")
+	rootCmd.PersistentFlags().BoolVar(&noCacheFlag, "no-cache", false, "Bypass the response cache even if enabled in config")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTLFlag, "cache-ttl", 24*time.Hour, "How long cached responses stay valid (0 = forever)")
+	rootCmd.PersistentFlags().BoolVar(&noHistoryFlag, "no-history", false, "Don't record this request in the local history")
+	rootCmd.PersistentFlags().BoolVar(&noValidateFlag, "no-validate", false, "Skip client-side checks of engine, language, copies and field lengths")
+	rootCmd.PersistentFlags().StringVar(&baseURLFlag, "base-url", "", "API base URL (env: WRITESONIC_BASE_URL, default "+writesonic.DefaultBaseURL+")")
+	rootCmd.PersistentFlags().StringVar(&proxyFlag, "proxy", "", "HTTP(S) proxy URL (overrides HTTPS_PROXY)")
+	rootCmd.PersistentFlags().StringVar(&caCertFlag, "ca-cert", "", "PEM file with extra CA certificates to trust")
+	rootCmd.PersistentFlags().StringVar(&clientCertFlag, "client-cert", "", "PEM client certificate for mTLS")
+	rootCmd.PersistentFlags().StringVar(&clientKeyFlag, "client-key", "", "PEM client private key for mTLS")
+	rootCmd.PersistentFlags().StringVar(&recordFlag, "record", "", "Save API requests and responses to cassettes in this directory (API key redacted)")
+	rootCmd.PersistentFlags().StringVar(&replayFlag, "replay", "", "Answer API requests from cassettes in this directory")
+	rootCmd.PersistentFlags().BoolVar(&replayStrictFlag, "replay-strict", false, "With --replay, fail on requests that have no cassette instead of calling the API")
+	rootCmd.PersistentFlags().DurationVar(&timeoutFlag, "timeout", 5*time.Minute, "Per-request timeout (0 disables)")
+	rootCmd.PersistentFlags().IntVar(&retriesFlag, "retries", 3, "Retries for transient API failures (429, 502-504, network); 0 disables")
+	rootCmd.PersistentFlags().DurationVar(&retryDelayFlag, "retry-delay", time.Second, "Initial retry backoff, doubled on each attempt")
+	rootCmd.PersistentFlags().DurationVar(&retryMaxDelayFlag, "retry-max-delay", 30*time.Second, "Maximum delay between retries (also caps Retry-After)")
 
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		runningCmd, runningArgs = cmd, args
+		if err := checkPick(); err != nil {
+			return err
+		}
 		if isAuthCommand(cmd) {
 			return nil
 		}
-		var err error
-		cfg, err = config.Load()
+		if shell != nil && !isOfflineCommand(cmd) && shell.reuse(cmd) {
+			applyConfigDefaults()
+			return nil
+		}
+		layers, err := loadConfigLayers()
 		if err != nil {
-			return fmt.Errorf("load config: %w", err)
+			return err
 		}
-
-		key := resolveAPIKey()
-		if key == "" {
-			return fmt.Errorf("no API key found — run: writesonic auth set-key <your-key>\n" +
-				"Or set the WRITESONIC_API_KEY environment variable")
+		cfg = layers.effective
+		name := layers.profile
+		if isOfflineCommand(cmd) {
+			return nil
 		}
-		client = api.NewClient(key)
 
-		// Apply config defaults if flags not set
-		if engineFlag == "" {
-			if cfg.DefaultEngine != "" {
-				engineFlag = cfg.DefaultEngine
-			} else {
-				engineFlag = "good"
+		key, _, err := resolveAPIKeyFrom(cfg, name)
+		if err != nil {
+			return err
+		}
+		if key == "" {
+			if !replayStrictFlag {
+				return errNoAPIKey
 			}
+			// Strict replays never reach the API, so no key is needed.
+			key = "replay"
 		}
-		if langFlag == "" {
-			if cfg.DefaultLanguage != "" {
-				langFlag = cfg.DefaultLanguage
-			} else {
-				langFlag = "en"
+		policy, err := retryPolicy(cmd)
+		if err != nil {
+			return err
+		}
+		timeout := timeoutFlag
+		if !cmd.Flags().Changed("timeout") && cfg.Timeout != "" {
+			timeout, err = time.ParseDuration(cfg.Timeout)
+			if err != nil {
+				return fmt.Errorf("config timeout: %w", err)
 			}
 		}
-		if copiesFlag == 0 {
-			if cfg.DefaultCopies > 0 {
-				copiesFlag = cfg.DefaultCopies
-			} else {
-				copiesFlag = 1
+		opts, err := networkOptions()
+		if err != nil {
+			return err
+		}
+		if cacheEnabled() {
+			cache, err := responseCache(cmd)
+			if err != nil {
+				return err
 			}
+			opts = append(opts, writesonic.WithCache(cache))
+		}
+		if !noHistoryFlag && !cfg.DisableHistory {
+			opts = append(opts, writesonic.WithObserver(recordHistory()))
 		}
+		client = writesonic.NewClient(key, append(opts,
+			writesonic.WithTimeout(timeout),
+			writesonic.WithRetryPolicy(policy),
+			writesonic.WithValidation(!noValidateFlag),
+			writesonic.WithRetryNotify(func(attempt int, wait time.Duration, err error) {
+				fmt.Fprintf(os.Stderr, "retrying in %s (attempt %d/%d): %v\n",
+					wait.Round(time.Millisecond), attempt+1, policy.MaxAttempts, err)
+			}),
+		)...)
+		if shell != nil {
+			shell.keep(cmd)
+		}
+		applyConfigDefaults()
 		return nil
 	}
 }
 
+// applyConfigDefaults fills --engine, --lang and --copies from the config
+// when they weren't given.
+func applyConfigDefaults() {
+	if engineFlag == "" {
+		if cfg.DefaultEngine != "" {
+			engineFlag = cfg.DefaultEngine
+		} else {
+			engineFlag = "good"
+		}
+	}
+	if langFlag == "" {
+		if cfg.DefaultLanguage != "" {
+			langFlag = cfg.DefaultLanguage
+		} else {
+			langFlag = "en"
+		}
+	}
+	if copiesFlag == 0 {
+		if cfg.DefaultCopies > 0 {
+			copiesFlag = cfg.DefaultCopies
+		} else {
+			copiesFlag = 1
+		}
+	}
+}
+
+// retryPolicy builds the client retry policy from flags, falling back to
+// config values for any flag not set explicitly.
+func retryPolicy(cmd *cobra.Command) (writesonic.RetryPolicy, error) {
+	policy := writesonic.DefaultRetryPolicy()
+	flags := cmd.Flags()
+
+	retries := retriesFlag
+	if !flags.Changed("retries") && cfg.MaxRetries != nil {
+		retries = *cfg.MaxRetries
+	}
+	if retries < 0 {
+		return policy, fmt.Errorf("--retries must be >= 0")
+	}
+	policy.MaxAttempts = retries + 1
+
+	policy.BaseDelay = retryDelayFlag
+	if !flags.Changed("retry-delay") && cfg.RetryDelay != "" {
+		d, err := time.ParseDuration(cfg.RetryDelay)
+		if err != nil {
+			return policy, fmt.Errorf("config retry_delay: %w", err)
+		}
+		policy.BaseDelay = d
+	}
+
+	policy.MaxDelay = retryMaxDelayFlag
+	if !flags.Changed("retry-max-delay") && cfg.RetryMaxDelay != "" {
+		d, err := time.ParseDuration(cfg.RetryMaxDelay)
+		if err != nil {
+			return policy, fmt.Errorf("config retry_max_delay: %w", err)
+		}
+		policy.MaxDelay = d
+	}
+	return policy, nil
+}
+
+// networkOptions returns client options for the base URL and transport,
+// resolved from flags, then environment, then config.
+func networkOptions() ([]writesonic.Option, error) {
+	var opts []writesonic.Option
+
+	baseURL := firstNonEmpty(baseURLFlag, os.Getenv("WRITESONIC_BASE_URL"), cfg.BaseURL)
+	if baseURL != "" {
+		opts = append(opts, writesonic.WithBaseURL(baseURL))
+	} else {
+		baseURL = writesonic.DefaultBaseURL
+	}
+
+	tc := api.TransportConfig{
+		ProxyURL:       firstNonEmpty(proxyFlag, cfg.ProxyURL),
+		CACertFile:     firstNonEmpty(caCertFlag, cfg.CACertFile),
+		ClientCertFile: firstNonEmpty(clientCertFlag, cfg.ClientCertFile),
+		ClientKeyFile:  firstNonEmpty(clientKeyFlag, cfg.ClientKeyFile),
+	}
+	var rt http.RoundTripper
+	if !tc.IsZero() {
+		var err error
+		if rt, err = api.NewTransport(tc); err != nil {
+			return nil, err
+		}
+	}
+
+	if replayStrictFlag && replayFlag == "" {
+		return nil, fmt.Errorf("--replay-strict requires --replay")
+	}
+	if replayStrictFlag && recordFlag != "" {
+		return nil, fmt.Errorf("--replay-strict can't be combined with --record")
+	}
+	if recordFlag != "" {
+		rt = api.NewRecorder(recordFlag, baseURL, rt)
+	}
+	if replayFlag != "" {
+		// Unmatched requests fall through to the recorder, if any, so
+		// --replay and --record on one directory fill in missing cassettes.
+		rt = api.NewReplayer(replayFlag, baseURL, replayStrictFlag, rt)
+	}
+	if rt != nil {
+		opts = append(opts, writesonic.WithTransport(rt))
+	}
+	return opts, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// newScheduler builds a request scheduler from --concurrency and --rate-limit,
+// falling back to config. Progress goes to stderr so stdout stays clean.
+func newScheduler() *api.Scheduler {
+	concurrency := concurrencyFlag
+	if concurrency <= 0 {
+		concurrency = cfg.Concurrency
+	}
+	limits := map[string]int{}
+	for k, v := range cfg.RateLimits {
+		limits[k] = v
+	}
+	for k, v := range rateLimitFlag {
+		limits[k] = v
+	}
+	opts := []api.SchedulerOption{
+		api.WithProgress(os.Stderr, isatty.IsTerminal(os.Stderr.Fd())),
+	}
+	for engine, perMinute := range limits {
+		if engine == "default" {
+			engine = ""
+		}
+		opts = append(opts, api.WithRateLimit(engine, perMinute))
+	}
+	return api.NewScheduler(concurrency, opts...)
+}
+
+// profileName returns the selected profile and where the choice came from:
+// --profile, then WRITESONIC_PROFILE, then the config's active_profile.
+func profileName(c *config.Config) (name, source string) {
+	switch {
+	case profileFlag != "":
+		return profileFlag, "--profile flag"
+	case os.Getenv("WRITESONIC_PROFILE") != "":
+		return os.Getenv("WRITESONIC_PROFILE"), "WRITESONIC_PROFILE"
+	case c.ActiveProfile != "":
+		return c.ActiveProfile, "config file"
+	}
+	return config.DefaultProfile, "default"
+}
+
 // resolveEnv returns the value of the first non-empty environment variable from the given names.
 func resolveEnv(names ...string) string {
 	for _, name := range names {
@@ -100,17 +354,52 @@ func resolveEnv(names ...string) string {
 	return ""
 }
 
-func resolveAPIKey() string {
+// resolveAPIKeyFrom returns the API key for profile from the environment, the
+// config file or the configured credential store, and a description of where
+// it came from. An empty key with a nil error means none is set.
+func resolveAPIKeyFrom(c *config.Config, profile string) (key, source string, err error) {
 	if k := resolveEnv(
 		"WRITESONIC_API_KEY", "WRITESONIC_KEY", "WRITESONIC_API", "API_KEY_WRITESONIC", "API_WRITESONIC", "WRITESONIC_PK", "WRITESONIC_PUBLIC",
 		"WRITESONIC_API_SECRET", "WRITESONIC_SECRET_KEY", "WRITESONIC_API_SECRET_KEY", "WRITESONIC_SECRET", "SECRET_WRITESONIC", "API_SECRET_WRITESONIC", "SK_WRITESONIC", "WRITESONIC_SK",
 	); k != "" {
-		return k
+		return k, "environment variable", nil
 	}
-	if cfg != nil && cfg.APIKey != "" {
-		return cfg.APIKey
+	if c == nil {
+		return "", "", nil
 	}
-	return ""
+	if c.APIKey != "" {
+		return c.APIKey, "config file", nil
+	}
+	store, err := credentialStore(c)
+	if err != nil || store.Name() == config.StoreConfig {
+		return "", "", err
+	}
+	k, err := store.Get(profile)
+	if errors.Is(err, config.ErrNoCredential) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return k, store.Name(), nil
+}
+
+// annotationOffline marks commands (and their subcommands) that load config
+// but never call the API, so they run without an API key.
+const annotationOffline = "writesonic/offline"
+
+func isOfflineCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if v, ok := c.Annotations[annotationOffline]; ok {
+			return v == "true"
+		}
+	}
+	return false
+}
+
+// commandName returns the command path without the binary name, e.g. "copy cta".
+func commandName(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
 }
 
 func isAuthCommand(cmd *cobra.Command) bool {
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/internal/workflow"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+var (
+	runVars    []string
+	runState   string
+	runRestart bool
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run <workflow.yaml>",
+	Short: "Run a multi-step workflow from a YAML file",
+	Long: `Run the steps of a workflow file in order. Each step calls a command that
+batch supports, with its flags under "with":
+
+  name: launch-copy
+  vars:
+    product: CloudStore
+    description: Cloud storage for small teams
+    features: [Encryption, Sync, Sharing]
+  steps:
+    - id: headline
+      command: landing headline
+      with: {name: "{{ .vars.product }}", desc: "{{ .vars.description }}"}
+    - id: pas
+      command: copy pas
+      engine: premium
+      with: {name: "{{ .vars.product }}", desc: "{{ .steps.headline.text }}"}
+    - id: features
+      command: write paragraph
+      foreach: vars.features
+      with: {topic: "{{ .vars.product }}: {{ .item }}"}
+      output: "out/feature-{{ .index }}.md"
+    - id: cta
+      command: copy cta
+      if: '{{ ne .vars.cta "no" }}'
+      with: {name: "{{ .vars.product }}"}
+
+Values are Go templates over .vars, .steps.ID (text, texts, results, items,
+skipped) and, in foreach steps, .item and .index. foreach takes a list, a path
+such as vars.features or steps.ideas.texts, or a template split into lines.
+Steps may set engine, language and copies; workflow-level engine and language
+apply when the matching flag isn't given.
+
+Every completed call is saved in a state file (<workflow>.state.json). Running
+again reuses calls whose inputs haven't changed, so a failed run resumes from
+the failing step without paying again for earlier ones; --restart starts over.`,
+	Example: `  writesonic run launch.yaml
+  writesonic run launch.yaml --var product=Acme --var cta=no
+  writesonic run launch.yaml --restart --json > results.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorkflow,
+}
+
+func init() {
+	runCmd.Flags().StringArrayVar(&runVars, "var", nil, "Set a workflow variable: NAME=VALUE (repeatable)")
+	runCmd.Flags().StringVar(&runState, "state", "", "State file (default <workflow>.state.json)")
+	runCmd.Flags().BoolVar(&runRestart, "restart", false, "Ignore saved state and run every step again")
+	runCmd.Flags().StringVar(&onExistsFlag, "on-exists", string(output.Overwrite), "When a step's output file exists: overwrite, skip or suffix")
+	rootCmd.AddCommand(runCmd)
+}
+
+func runWorkflow(cmd *cobra.Command, args []string) error {
+	w, err := workflow.Load(args[0])
+	if err != nil {
+		return err
+	}
+	if err := w.SetVars(runVars); err != nil {
+		return fmt.Errorf("--var: %w", err)
+	}
+	if err := w.Check(commandFlags); err != nil {
+		return err
+	}
+	policy, err := output.ParseExistsPolicy(onExistsFlag)
+	if err != nil {
+		return fmt.Errorf("--on-exists: %w", err)
+	}
+
+	statePath := runState
+	if statePath == "" {
+		statePath = strings.TrimSuffix(args[0], ".yaml")
+		statePath = strings.TrimSuffix(statePath, ".yml") + ".state.json"
+	}
+	if runRestart {
+		if err := os.Remove(statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	state, err := workflow.LoadState(statePath, args[0])
+	if err != nil {
+		return err
+	}
+
+	r := &workflow.Runner{
+		Call:     callWorkflowStep,
+		State:    state,
+		Log:      os.Stderr,
+		Engine:   engineFlag,
+		Language: langFlag,
+		Copies:   copiesFlag,
+		Write: func(path string, data []byte) (string, error) {
+			written, err := output.WriteFile(path, data, policy)
+			if err == nil && written == "" {
+				fmt.Fprintf(os.Stderr, "skipped %s (exists)\n", path)
+			} else if err == nil {
+				fmt.Fprintf(os.Stderr, "wrote %s\n", written)
+			}
+			return written, err
+		},
+	}
+	if w.Engine != "" && !cmd.Flags().Changed("engine") {
+		r.Engine = w.Engine
+	}
+	if w.Language != "" && !cmd.Flags().Changed("lang") {
+		r.Language = w.Language
+	}
+
+	outputs, err := r.Run(cmd.Context(), w)
+	if err != nil {
+		// The steps that finished were paid for; show them before the error.
+		if len(outputs) > 0 {
+			if perr := printWorkflowOutputs(outputs); perr != nil {
+				return perr
+			}
+		}
+		var se *workflow.StepError
+		if errors.As(err, &se) {
+			se.Err = explainColumnError(commandOf(w, se.Step), se.Err)
+		}
+		if len(state.Calls) > 0 {
+			return fmt.Errorf("%w; rerun to resume from this step (state in %s)", err, statePath)
+		}
+		return err
+	}
+	return printWorkflowOutputs(outputs)
+}
+
+// printWorkflowOutputs prints each step's results under a header, or all of
+// them as JSON.
+func printWorkflowOutputs(outputs []workflow.StepOutput) error {
+	if output.IsJSON(jsonFlag, prettyFlag) {
+		return output.PrintJSON(outputs, prettyFlag)
+	}
+	for i, out := range outputs {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("--- %s (%s) ---\n", out.ID, out.Command)
+		if out.Skipped {
+			fmt.Println("(skipped)")
+			continue
+		}
+		var texts []string
+		for _, it := range out.Items {
+			texts = append(texts, it.Texts...)
+		}
+		fmt.Println(strings.Join(texts, "\n\n"))
+	}
+	return nil
+}
+
+// callWorkflowStep runs one workflow call through the batch command specs.
+func callWorkflowStep(ctx context.Context, command string, fields map[string]interface{}) (workflow.Result, error) {
+	results, err := callCommand(ctx, command, fields)
+	if err != nil {
+		return workflow.Result{}, err
+	}
+	raw, err := json.Marshal(results)
+	if err != nil {
+		return workflow.Result{}, err
+	}
+	res := workflow.Result{Results: raw}
+	switch results := results.(type) {
+	case []writesonic.Result:
+		for _, r := range results {
+			res.Texts = append(res.Texts, r.Text)
+		}
+	case []writesonic.LandingPage:
+		if res.Texts, err = landingMarkdown(results); err != nil {
+			return workflow.Result{}, err
+		}
+	}
+	return res, nil
+}
+
+// commandFlags lists the flags a workflow step may set for command.
+func commandFlags(command string) ([]string, error) {
+	spec, ok := batchSpecs[command]
+	if !ok {
+		return nil, fmt.Errorf("unknown command %q (supported: %s)", command, strings.Join(batchCommandNames(), ", "))
+	}
+	names := make([]string, 0, len(spec.fields))
+	for name := range spec.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func commandOf(w *workflow.Workflow, id string) string {
+	for _, s := range w.Steps {
+		if s.ID == id {
+			return s.Command
+		}
+	}
+	return ""
+}
@@ -0,0 +1,221 @@
+package cmd
+
+// save.go writes generated results to files instead of stdout: one file per
+// copy, named from a template, written atomically.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+var (
+	outputFlag     string
+	outputDirFlag  string
+	outputNameFlag string
+	onExistsFlag   string
+
+	// runningCmd and runningArgs are the command being executed, for naming
+	// output files.
+	runningCmd  *cobra.Command
+	runningArgs []string
+)
+
+const defaultOutputName = "{{.Command}}-{{.Slug}}-{{.Index}}.{{.Ext}}"
+
+// outputFlags are --format and the flags for saving results. They are shared
+// by the commands that print results with printResults or printLandingPages,
+// and looked up by the shell for session defaults.
+var outputFlags = newOutputFlags()
+
+func newOutputFlags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("output", pflag.ContinueOnError)
+	fs.StringVar(&formatFlag, "format", "", "Output format: "+strings.Join(output.FormatNames(), ", ")+" (default text on a terminal, json otherwise)")
+	fs.StringVarP(&outputFlag, "output", "o", "", "Write results to this file (one file per copy; may be a name template)")
+	fs.StringVar(&outputDirFlag, "output-dir", "", "Write one file per copy into this directory")
+	fs.StringVar(&outputNameFlag, "output-name", defaultOutputName, "File name template for --output-dir")
+	fs.StringVar(&onExistsFlag, "on-exists", string(output.Overwrite), "When an output file exists: overwrite, skip or suffix")
+	return fs
+}
+
+// addOutputFlags adds the output flags to commands that print results.
+// Commands that write elsewhere (batch, run, pipeline) define their own.
+func addOutputFlags(cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		c.Flags().AddFlagSet(outputFlags)
+	}
+}
+
+// subjectFlags are checked in order for the text a file name's slug is
+// derived from.
+var subjectFlags = []string{"title", "topic", "name", "question", "content-file", "intro-file"}
+
+// outputFile is one generated copy ready to be saved.
+type outputFile struct {
+	data []byte
+	ext  string
+}
+
+// outputName is the data available to the --output-name template.
+type outputName struct {
+	Command  string // command path with spaces as dashes, e.g. "article-instant"
+	Slug     string // slug of the title, topic, name or input file
+	Index    int    // 1-based copy number
+	Count    int    // number of copies
+	Engine   string
+	Language string
+	Date     string // YYYY-MM-DD
+	Ext      string // extension of the output format, e.g. "md" or "json"
+}
+
+// saveRequested reports whether results go to files rather than stdout.
+func saveRequested() bool {
+	return outputFlag != "" || outputDirFlag != ""
+}
+
+// saveResults saves one file per text result.
+func saveResults(results []writesonic.Result) error {
+	return saveDocument(resultsDocument(results), "md")
+}
+
+// saveLandingPages saves one file per landing page.
+func saveLandingPages(pages []writesonic.LandingPage) error {
+	return saveDocument(landingDocument(pages), "txt")
+}
+
+// saveDocument renders each result of d to its own file in the --format
+// format. textExt is the extension used for the default text format.
+func saveDocument(d output.Document, textExt string) error {
+	f, err := outputFormat(true)
+	if err != nil {
+		return err
+	}
+	ext := f.Ext
+	if f.Name == "text" && formatFlag == "" {
+		ext = textExt
+	}
+	files := make([]outputFile, len(d.Records))
+	for i := range d.Records {
+		var buf bytes.Buffer
+		if f.Name == "json" {
+			// A file holds one result, so write the object, not an array.
+			enc := json.NewEncoder(&buf)
+			if prettyFlag {
+				enc.SetIndent("", "  ")
+			}
+			err = enc.Encode(d.Items[i])
+		} else {
+			err = f.Render(&buf, output.Document{
+				Items:   d.Items[i : i+1],
+				Records: d.Records[i : i+1],
+				Pretty:  prettyFlag,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		files[i] = outputFile{data: buf.Bytes(), ext: ext}
+	}
+	return saveOutputs(files)
+}
+
+// saveOutputs writes files according to --output/--output-dir, --output-name
+// and --on-exists, and reports each path on stderr.
+func saveOutputs(files []outputFile) error {
+	if outputFlag != "" && outputDirFlag != "" {
+		return fmt.Errorf("use either --output or --output-dir, not both")
+	}
+	policy, err := output.ParseExistsPolicy(onExistsFlag)
+	if err != nil {
+		return fmt.Errorf("--on-exists: %w", err)
+	}
+	pattern := outputNameFlag
+	if outputFlag != "" {
+		pattern = outputFlag
+		if !strings.Contains(pattern, "{{") && len(files) > 1 {
+			ext := filepath.Ext(pattern)
+			pattern = strings.TrimSuffix(pattern, ext) + "-{{.Index}}" + ext
+		}
+	}
+	tmpl, err := template.New("output").Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid output name template: %w", err)
+	}
+
+	name := outputName{
+		Command:  strings.ReplaceAll(commandName(runningCmd), " ", "-"),
+		Slug:     outputSlug(),
+		Count:    len(files),
+		Engine:   engineFlag,
+		Language: langFlag,
+		Date:     time.Now().Format("2006-01-02"),
+	}
+	paths := make([]string, len(files))
+	seen := map[string]bool{}
+	for i, f := range files {
+		name.Index = i + 1
+		name.Ext = f.ext
+		var b strings.Builder
+		if err := tmpl.Execute(&b, name); err != nil {
+			return fmt.Errorf("invalid output name template: %w", err)
+		}
+		path := b.String()
+		if outputDirFlag != "" {
+			path = filepath.Join(outputDirFlag, path)
+		}
+		if seen[path] && policy != output.Suffix {
+			return fmt.Errorf("output name %q is the same for several copies; add {{.Index}} to it", path)
+		}
+		seen[path] = true
+		paths[i] = path
+	}
+
+	for i, f := range files {
+		written, err := output.WriteFile(paths[i], f.data, policy)
+		if err != nil {
+			return err
+		}
+		if written == "" {
+			fmt.Fprintf(os.Stderr, "skipped %s (exists)\n", paths[i])
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "wrote %s\n", written)
+	}
+	return nil
+}
+
+// outputSlug derives the {{.Slug}} value from the command's subject: the
+// first set subject flag, else the first input file, else "output".
+func outputSlug() string {
+	if runningCmd != nil {
+		for _, name := range subjectFlags {
+			f := runningCmd.Flags().Lookup(name)
+			if f == nil || f.Value.String() == "" || f.Value.String() == "-" {
+				continue
+			}
+			v := f.Value.String()
+			if strings.HasSuffix(name, "-file") {
+				v = strings.TrimSuffix(filepath.Base(v), filepath.Ext(v))
+			}
+			if slug := output.Slugify(v); slug != "" {
+				return slug
+			}
+		}
+	}
+	for _, arg := range runningArgs {
+		if slug := output.Slugify(strings.TrimSuffix(filepath.Base(arg), filepath.Ext(arg))); slug != "" {
+			return slug
+		}
+	}
+	return "output"
+}
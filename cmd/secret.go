@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/the20100/writesonic-cli/internal/config"
+	"golang.org/x/term"
+)
+
+// credentialStore returns the backend selected by credential_store in c,
+// prompting for the encrypted-file passphrase only when it is needed.
+func credentialStore(c *config.Config) (config.CredentialStore, error) {
+	return c.CredentialBackend(passphrasePrompt)
+}
+
+// passphrasePrompt reads the encrypted-file passphrase from
+// WRITESONIC_PASSPHRASE, or asks for it on the terminal without echo.
+func passphrasePrompt(confirm bool) ([]byte, error) {
+	if p := os.Getenv("WRITESONIC_PASSPHRASE"); p != "" {
+		return []byte(p), nil
+	}
+	pass, err := readSecret("Credentials passphrase: ")
+	if err != nil {
+		return nil, fmt.Errorf("%w (or set WRITESONIC_PASSPHRASE)", err)
+	}
+	if confirm {
+		again, err := readSecret("Repeat passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(pass, again) {
+			return nil, fmt.Errorf("passphrases do not match")
+		}
+	}
+	return pass, nil
+}
+
+// readSecret prompts on stderr and reads a line from the terminal without
+// echoing it. It uses the controlling terminal when stdin is redirected.
+func readSecret(prompt string) ([]byte, error) {
+	tty := os.Stdin
+	if !isatty.IsTerminal(tty.Fd()) {
+		f, err := os.Open("/dev/tty")
+		if err != nil {
+			return nil, fmt.Errorf("no terminal available to prompt for input")
+		}
+		defer f.Close()
+		tty = f
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(int(tty.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("read input: %w", err)
+	}
+	return b, nil
+}
+
+// readAPIKeyInput reads an API key from a hidden prompt when stdin is a
+// terminal, or from the first line of stdin otherwise.
+func readAPIKeyInput() (string, error) {
+	var key string
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		b, err := readSecret("Writesonic API key: ")
+		if err != nil {
+			return "", err
+		}
+		key = string(b)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("read stdin: %w", err)
+		}
+		key = line
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", fmt.Errorf("no API key given")
+	}
+	return key, nil
+}
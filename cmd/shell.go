@@ -0,0 +1,589 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/peterh/liner"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/the20100/writesonic-cli/internal/config"
+	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Start an interactive prompt that keeps the client between commands",
+	Long: `Start an interactive prompt. Type commands without the "writesonic" prefix;
+the config is loaded and the API client built once, then reused by every
+command until a client flag (--profile, --base-url, --cache, --timeout, ...)
+changes or an auth, config or profile command runs.
+
+` + shellCommandsHelp + `
+$last is the first result of the last command that printed results and
+$last[N] its Nth result, e.g.:
+
+  rewrite tone --content $last[2] --tone formal
+
+They expand inside double quotes but not single quotes; \$ is a literal $.
+Tab completes commands, flags and the values of --engine, --lang and
+--format. History is kept in shell_history in the config directory.`,
+	Example: `  writesonic shell
+  writesonic shell --engine premium`,
+	Args: cobra.NoArgs,
+	RunE: runShell,
+}
+
+const shellCommandsHelp = `Shell commands:
+  set [NAME VALUE]   set a global or output flag for the rest of the session,
+                     e.g. "set engine premium" (no arguments lists them)
+  unset NAME         drop a session flag
+  results            list the results of the last command
+  help [COMMAND]     help for writesonic or a command
+  exit, quit         leave the shell (or Ctrl-D)
+`
+
+func init() {
+	addOutputFlags(shellCmd)
+	rootCmd.AddCommand(shellCmd)
+}
+
+// clientFlags are the flags the API client is built from; the shell builds a
+// new client when any of them changes.
+var clientFlags = []string{
+	"profile", "base-url", "proxy", "ca-cert", "client-cert", "client-key",
+	"record", "replay", "replay-strict", "timeout", "retries", "retry-delay",
+	"retry-max-delay", "cache", "no-cache", "cache-ttl", "no-history", "no-validate",
+}
+
+// shellSession is the state kept between shell commands.
+type shellSession struct {
+	defaults map[string]string // session flags from "set"
+	last     []string          // results of the last command that had any
+	next     []string          // results of the command running now
+
+	client    *writesonic.Client
+	cfg       *config.Config
+	clientKey string // clientFlags values client was built with
+}
+
+// shell is the running session, nil outside the shell.
+var shell *shellSession
+
+// reuse reports whether cmd can run with the session's client, and if so
+// makes it the current one.
+func (s *shellSession) reuse(cmd *cobra.Command) bool {
+	if s.client == nil || s.clientKey != clientFlagsKey(cmd) {
+		return false
+	}
+	client, cfg = s.client, s.cfg
+	return true
+}
+
+// keep remembers the client just built for cmd.
+func (s *shellSession) keep(cmd *cobra.Command) {
+	s.client, s.cfg, s.clientKey = client, cfg, clientFlagsKey(cmd)
+}
+
+func clientFlagsKey(cmd *cobra.Command) string {
+	var b strings.Builder
+	for _, name := range clientFlags {
+		if f := cmd.Flags().Lookup(name); f != nil {
+			fmt.Fprintf(&b, "%s=%s\n", name, f.Value.String())
+		}
+	}
+	return b.String()
+}
+
+// rememberResults records the texts a command produced, for $last.
+func rememberResults(texts []string) {
+	if shell != nil {
+		shell.next = texts
+	}
+}
+
+func runShell(cmd *cobra.Command, args []string) error {
+	if shell != nil {
+		return fmt.Errorf("already in the shell")
+	}
+	shell = &shellSession{defaults: map[string]string{}}
+	defer func() { shell = nil }()
+	// Flags given to "writesonic shell" become session defaults.
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if _, err := shellFlag(f.Name); err == nil {
+			shell.defaults[f.Name] = f.Value.String()
+		}
+	})
+	shell.keep(cmd)
+	resetFlags(rootCmd)
+
+	line := liner.NewLiner()
+	defer line.Close()
+	line.SetCtrlCAborts(true)
+	line.SetWordCompleter(completeShell)
+	historyPath := ""
+	if dir, err := config.Dir(); err == nil {
+		historyPath = filepath.Join(dir, "shell_history")
+		if f, err := os.Open(historyPath); err == nil {
+			line.ReadHistory(f)
+			f.Close()
+		}
+	}
+	defer func() {
+		if historyPath == "" {
+			return
+		}
+		if err := os.MkdirAll(filepath.Dir(historyPath), 0o700); err != nil {
+			return
+		}
+		if f, err := os.OpenFile(historyPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600); err == nil {
+			line.WriteHistory(f)
+			f.Close()
+		}
+	}()
+
+	// Ctrl-C cancels the running command, not the shell.
+	ctx := context.WithoutCancel(cmd.Context())
+	for {
+		text, err := line.Prompt("writesonic> ")
+		if errors.Is(err, liner.ErrPromptAborted) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			fmt.Println()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if historyLine(text) {
+			line.AppendHistory(text)
+		}
+
+		words, err := splitShellLine(text, shell.expand)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		switch words[0] {
+		case "exit", "quit":
+			return nil
+		case "set":
+			err = shellSet(words[1:])
+		case "unset":
+			err = shellUnset(words[1:])
+		case "results":
+			shellShowResults()
+		case "help":
+			if len(words) > 1 && slices.Contains(shellBuiltins, words[1]) {
+				fmt.Print(shellCommandsHelp)
+				break
+			}
+			err = runShellCommand(ctx, words)
+			if len(words) == 1 {
+				fmt.Print("\n" + shellCommandsHelp)
+			}
+		default:
+			err = runShellCommand(ctx, words)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+	}
+}
+
+// runShellCommand runs one command line through the root command with the
+// session flags in front, so flags on the line win.
+func runShellCommand(ctx context.Context, words []string) error {
+	if words[0] == "shell" {
+		return fmt.Errorf("already in the shell")
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	args := sessionFlags(shell.defaults, words)
+	args = append(args, words...)
+
+	shell.next = nil
+	setContexts(rootCmd, ctx)
+	rootCmd.SetArgs(args)
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	resetFlags(rootCmd)
+	if cmd != nil {
+		// These may change the key or the config the client was built from.
+		name := commandName(cmd)
+		if isAuthCommand(cmd) || strings.HasPrefix(name, "config") || strings.HasPrefix(name, "profile") {
+			shell.client = nil
+		}
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+			return fmt.Errorf("interrupted: request cancelled")
+		}
+		if cmd != nil {
+			err = explainFlagError(commandName(cmd), err)
+		}
+		return err
+	}
+	if shell.next != nil {
+		shell.last = shell.next
+	}
+	return nil
+}
+
+// sessionFlags returns the session flags the command in words takes, as
+// --name=value arguments. Output flags are left off commands without them,
+// which would reject them.
+func sessionFlags(defaults map[string]string, words []string) []string {
+	if words[0] == "help" {
+		return nil
+	}
+	target, _, err := rootCmd.Find(words)
+	if err != nil {
+		target = rootCmd
+	}
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		if target.Flags().Lookup(name) != nil || target.InheritedFlags().Lookup(name) != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		args = append(args, "--"+name+"="+defaults[name])
+	}
+	return args
+}
+
+// setContexts points every command at ctx; cobra only sets a subcommand's
+// context the first time it runs.
+func setContexts(c *cobra.Command, ctx context.Context) {
+	c.SetContext(ctx)
+	for _, sub := range c.Commands() {
+		setContexts(sub, ctx)
+	}
+}
+
+// resetFlags puts every flag back to its default, since cobra keeps parsed
+// values from one execution to the next.
+func resetFlags(c *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		switch v := f.Value.(type) {
+		case pflag.SliceValue:
+			var def []string
+			if s := strings.Trim(f.DefValue, "[]"); s != "" {
+				def = strings.Split(s, ",")
+			}
+			v.Replace(def)
+		default:
+			if !strings.HasPrefix(f.Value.Type(), "stringTo") {
+				f.Value.Set(f.DefValue)
+			}
+		}
+		f.Changed = false
+	}
+	c.Flags().VisitAll(reset)
+	c.PersistentFlags().VisitAll(reset)
+	for _, sub := range c.Commands() {
+		resetFlags(sub)
+	}
+	rateLimitFlag = map[string]int{}
+}
+
+// shellFlag looks up a global or output flag by name, with or without
+// dashes.
+func shellFlag(name string) (*pflag.Flag, error) {
+	name = strings.TrimLeft(name, "-")
+	f := rootCmd.PersistentFlags().Lookup(name)
+	if f == nil {
+		f = outputFlags.Lookup(name)
+	}
+	if f == nil {
+		return nil, fmt.Errorf("%q isn't a global or output flag", name)
+	}
+	return f, nil
+}
+
+func shellSet(args []string) error {
+	if len(args) == 0 {
+		names := make([]string, 0, len(shell.defaults))
+		for name := range shell.defaults {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%s = %s\n", name, shell.defaults[name])
+		}
+		return nil
+	}
+	f, err := shellFlag(args[0])
+	if err != nil {
+		return err
+	}
+	value := strings.Join(args[1:], " ")
+	if value == "" {
+		if f.Value.Type() != "bool" {
+			return fmt.Errorf("usage: set %s VALUE", f.Name)
+		}
+		value = "true"
+	}
+	// Parse the value now, so a typo fails here rather than on every command.
+	err = f.Value.Set(value)
+	resetFlags(rootCmd)
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	shell.defaults[f.Name] = value
+	return nil
+}
+
+func shellUnset(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: unset NAME")
+	}
+	f, err := shellFlag(args[0])
+	if err != nil {
+		return err
+	}
+	delete(shell.defaults, f.Name)
+	return nil
+}
+
+func shellShowResults() {
+	if len(shell.last) == 0 {
+		fmt.Println("No results yet.")
+		return
+	}
+	for i, text := range shell.last {
+		fmt.Printf("$last[%d]  %s\n", i+1, output.Truncate(strings.Join(strings.Fields(text), " "), 70))
+	}
+}
+
+// expand resolves a result reference: "last" or "last[N]".
+func (s *shellSession) expand(ref string) (string, error) {
+	n := 1
+	if i := strings.IndexByte(ref, '['); i >= 0 {
+		var err error
+		if n, err = strconv.Atoi(ref[i+1 : len(ref)-1]); err != nil || n < 1 {
+			return "", fmt.Errorf("invalid reference $%s", ref)
+		}
+	}
+	if n > len(s.last) {
+		if len(s.last) == 0 {
+			return "", fmt.Errorf("$%s: no results yet", ref)
+		}
+		return "", fmt.Errorf("$%s: the last command returned %d result(s)", ref, len(s.last))
+	}
+	return s.last[n-1], nil
+}
+
+// historyLine reports whether a line may be kept in the history, which is
+// saved to disk. Lines that could carry an API key are left out: auth
+// set-key, which prompts for the key but would record one typed after it,
+// any --key flag, and config set of a secret setting.
+func historyLine(text string) bool {
+	words := strings.Fields(text)
+	for _, w := range words {
+		if w == "--key" || strings.HasPrefix(w, "--key=") {
+			return false
+		}
+	}
+	switch {
+	case len(words) >= 2 && words[0] == "auth" && words[1] == "set-key":
+		return false
+	case len(words) >= 3 && words[0] == "config" && words[1] == "set":
+		if s, _, err := config.LookupSetting(strings.Trim(words[2], `'"`)); err == nil && s.Secret {
+			return false
+		}
+	}
+	return true
+}
+
+// splitShellLine splits a line into words the way a POSIX shell does for
+// quotes and backslashes, expanding $last and $last[N] outside single
+// quotes.
+func splitShellLine(line string, expand func(ref string) (string, error)) ([]string, error) {
+	var (
+		words  []string
+		word   strings.Builder
+		inWord bool
+		quote  rune
+	)
+	rs := []rune(line)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\' && i+1 < len(rs):
+			next := rs[i+1]
+			if quote == '"' && next != '"' && next != '\\' && next != '$' {
+				word.WriteRune(r)
+			} else {
+				i++
+				word.WriteRune(next)
+			}
+			inWord = true
+		case r == '$' && strings.HasPrefix(string(rs[i+1:]), "last"):
+			end := i + 1 + len("last")
+			if end < len(rs) && rs[end] == '[' {
+				for end < len(rs) && rs[end] != ']' {
+					end++
+				}
+				if end == len(rs) {
+					return nil, fmt.Errorf("unterminated $last[")
+				}
+				end++
+			}
+			text, err := expand(string(rs[i+1 : end]))
+			if err != nil {
+				return nil, err
+			}
+			word.WriteString(text)
+			inWord = true
+			i = end - 1
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// shellBuiltins are the commands the shell handles itself.
+var shellBuiltins = []string{"exit", "quit", "results", "set", "unset"}
+
+// completeShell completes the word before the cursor: a command, a flag,
+// or the value of --engine, --lang or --format.
+func completeShell(line string, pos int) (string, []string, string) {
+	head, tail := line[:pos], line[pos:]
+	start := strings.LastIndexAny(head, " \t") + 1
+	prefix := head[start:]
+	words := strings.Fields(head[:start])
+
+	var candidates []string
+	switch {
+	case len(words) > 0 && (words[0] == "set" || words[0] == "unset"):
+		if len(words) == 1 {
+			add := func(f *pflag.Flag) {
+				candidates = append(candidates, f.Name+" ")
+			}
+			rootCmd.PersistentFlags().VisitAll(add)
+			outputFlags.VisitAll(add)
+		} else if len(words) == 2 && words[0] == "set" {
+			candidates = flagValues(strings.TrimLeft(words[1], "-"))
+		}
+	case len(words) > 0 && strings.HasPrefix(words[len(words)-1], "--") && flagValues(words[len(words)-1][2:]) != nil:
+		candidates = flagValues(words[len(words)-1][2:])
+	case strings.HasPrefix(prefix, "-"):
+		c := shellCommandFor(words)
+		add := func(f *pflag.Flag) {
+			if !f.Hidden {
+				candidates = append(candidates, "--"+f.Name+" ")
+			}
+		}
+		c.LocalFlags().VisitAll(add)
+		c.InheritedFlags().VisitAll(add)
+	default:
+		c := shellCommandFor(words)
+		for _, sub := range c.Commands() {
+			if sub.IsAvailableCommand() || sub.Name() == "help" {
+				candidates = append(candidates, sub.Name()+" ")
+			}
+		}
+		if c == rootCmd && len(words) == 0 {
+			for _, b := range shellBuiltins {
+				candidates = append(candidates, b+" ")
+			}
+		}
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			matches = append(matches, c)
+		}
+	}
+	sort.Strings(matches)
+	return head[:start], matches, tail
+}
+
+// shellCommandFor finds the command the words so far select.
+func shellCommandFor(words []string) *cobra.Command {
+	c := rootCmd
+	for _, w := range words {
+		if strings.HasPrefix(w, "-") {
+			continue
+		}
+		for _, sub := range c.Commands() {
+			if sub.Name() == w || sub.HasAlias(w) {
+				c = sub
+				break
+			}
+		}
+	}
+	return c
+}
+
+// flagValues lists the known values of a flag, with a trailing space.
+func flagValues(name string) []string {
+	var values []string
+	switch name {
+	case "engine":
+		values = writesonic.Engines
+	case "lang":
+		values = writesonic.Languages
+	case "format":
+		values = output.FormatNames()
+	default:
+		return nil
+	}
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = v + " "
+	}
+	return out
+}
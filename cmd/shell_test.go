@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHistoryLine(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		keep bool
+	}{
+		{"copy cta --name X --copies 3", true},
+		{"rewrite tone --content $last[2] --tone formal", true},
+		{"auth status", true},
+		{"config set default_engine premium", true},
+		{"auth set-key", false},
+		{"auth set-key sk-live-123", false},
+		{"auth  set-key --profile acme", false},
+		{"auth profile add acme --key sk-live-123", false},
+		{"auth profile add acme --key=sk-live-123", false},
+		{"config set api_key sk-live-123", false},
+		{"config set profiles.acme.api_key sk-live-123", false},
+		{`config set "api_key" sk-live-123`, false},
+	} {
+		if got := historyLine(tc.line); got != tc.keep {
+			t.Errorf("historyLine(%q) = %v, want %v", tc.line, got, tc.keep)
+		}
+	}
+}
+
+func TestSessionFlags(t *testing.T) {
+	defaults := map[string]string{"engine": "premium", "format": "markdown", "output-dir": "out"}
+	for _, tc := range []struct {
+		line string
+		want []string
+	}{
+		{"copy cta --name X", []string{"--engine=premium", "--format=markdown", "--output-dir=out"}},
+		{"history list --limit 5", []string{"--engine=premium"}},
+		{"pipeline blog --topic x", []string{"--engine=premium", "--output-dir=out"}},
+		{"help copy cta", nil},
+	} {
+		got := sessionFlags(defaults, strings.Fields(tc.line))
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("sessionFlags(%q) = %v, want %v", tc.line, got, tc.want)
+		}
+	}
+}
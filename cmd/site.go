@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/internal/output"
+	"github.com/the20100/writesonic-cli/internal/site"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+// site.go exports articles as static site content: Markdown with front
+// matter under the generator's content directory.
+
+var (
+	siteFlag        string
+	siteDirFlag     string
+	siteSlug        string
+	siteDescription string
+	siteKeywords    []string
+	siteDate        string
+	siteMeta        bool
+)
+
+// descriptionLength is the usual cut-off for search result snippets.
+const descriptionLength = 160
+
+// addSiteFlags registers the --site export flags on an article command.
+func addSiteFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&siteFlag, "site", "", "Write a content file for a static site: "+strings.Join(site.Names(), ", "))
+	cmd.Flags().StringVar(&siteDirFlag, "site-dir", ".", "Site root the content path is relative to")
+	cmd.Flags().StringVar(&siteSlug, "slug", "", "Post slug (default from the title)")
+	cmd.Flags().StringVar(&siteDescription, "description", "", "Front matter description (default from --meta or the first paragraph)")
+	cmd.Flags().StringSliceVar(&siteKeywords, "keyword", nil, "Tag for the front matter (repeatable or comma-separated)")
+	cmd.Flags().StringVar(&siteDate, "date", "", "Publication date, YYYY-MM-DD or RFC 3339 (default now)")
+	cmd.Flags().BoolVar(&siteMeta, "meta", false, "Call /meta-blog to fill the meta title and description")
+}
+
+// checkSiteFlags rejects --site values and combinations that can't work,
+// before any request is made.
+func checkSiteFlags() error {
+	if siteFlag == "" {
+		return nil
+	}
+	if _, err := site.Lookup(siteFlag); err != nil {
+		return fmt.Errorf("--site: %w", err)
+	}
+	if saveRequested() {
+		return fmt.Errorf("--site writes under --site-dir; don't combine it with --output or --output-dir")
+	}
+	if pickFlag {
+		return fmt.Errorf("--site exports every copy; don't combine it with --pick")
+	}
+	if strings.ContainsAny(siteSlug, `/\`) || siteSlug == "." || siteSlug == ".." {
+		return fmt.Errorf("--slug %q: must be a file name, without / or \\", siteSlug)
+	}
+	_, err := parseSiteDate()
+	return err
+}
+
+func parseSiteDate() (time.Time, error) {
+	if siteDate == "" {
+		return time.Now(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, siteDate); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", siteDate, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--date: want YYYY-MM-DD or RFC 3339, got %q", siteDate)
+	}
+	return t, nil
+}
+
+// exportSite writes each article as a content file for the --site
+// generator. With several copies, the slug of each gets a -N suffix.
+func exportSite(ctx context.Context, title string, results []writesonic.Result) error {
+	gen, err := site.Lookup(siteFlag)
+	if err != nil {
+		return err
+	}
+	date, err := parseSiteDate()
+	if err != nil {
+		return err
+	}
+	policy, err := output.ParseExistsPolicy(onExistsFlag)
+	if err != nil {
+		return fmt.Errorf("--on-exists: %w", err)
+	}
+	slug := siteSlug
+	if slug == "" {
+		slug = postSlug(title)
+	}
+
+	for i, r := range results {
+		page := site.Page{
+			Title:       title,
+			Slug:        slug,
+			Date:        date,
+			Language:    langFlag,
+			Tags:        siteKeywords,
+			Description: siteDescription,
+			Body:        r.Text,
+		}
+		if len(results) > 1 {
+			page.Slug = fmt.Sprintf("%s-%d", slug, i+1)
+		}
+		if siteMeta {
+			if err := fillMeta(ctx, &page); err != nil {
+				return err
+			}
+		}
+		if page.Description == "" {
+			page.Description = site.Excerpt(page.Body, descriptionLength)
+		}
+
+		data, err := gen.Render(page)
+		if err != nil {
+			return err
+		}
+		path := gen.Path(siteDirFlag, page)
+		written, err := output.WriteFile(path, data, policy)
+		if err != nil {
+			return err
+		}
+		if written == "" {
+			fmt.Fprintf(os.Stderr, "skipped %s (exists)\n", path)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "wrote %s\n", written)
+	}
+	return nil
+}
+
+// postSlug is the slug for a post titled title, or "post" for a title
+// without letters or digits, so the file never becomes a bare ".md".
+func postSlug(title string) string {
+	if slug := output.Slugify(title); slug != "" {
+		return slug
+	}
+	return "post"
+}
+
+// fillMeta asks /meta-blog for an SEO title and description for page. A
+// description given with --description is kept.
+func fillMeta(ctx context.Context, page *site.Page) error {
+	summary := page.Description
+	if summary == "" {
+		summary = site.Excerpt(page.Body, 1000)
+	}
+	if summary == "" {
+		summary = page.Title
+	}
+	results, err := client.MetaBlog(ctx, writesonic.MetaBlogRequest{
+		Title:       page.Title,
+		Description: summary,
+	}, writesonic.WithEngine(engineFlag), writesonic.WithLanguage(langFlag), writesonic.WithCopies(1))
+	if err != nil {
+		return fmt.Errorf("meta-blog: %w", err)
+	}
+	if len(results) == 0 {
+		return nil
+	}
+	title, description := site.ParseMeta(results[0].Text)
+	if title != page.Title {
+		page.MetaTitle = title
+	}
+	if page.Description == "" {
+		page.Description = description
+	}
+	return nil
+}
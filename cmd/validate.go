@@ -0,0 +1,79 @@
+package cmd
+
+// validate.go turns client-side validation errors from the API package into
+// messages that name the flag (or batch column) the user has to fix.
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+var noValidateFlag bool
+
+// paramFlags maps query parameters to the global flags that set them.
+var paramFlags = map[string]string{
+	"engine":     "engine",
+	"language":   "lang",
+	"num_copies": "copies",
+}
+
+// paramColumns maps query parameters to the batch columns that override them.
+var paramColumns = map[string]string{
+	"engine":     "engine",
+	"language":   "language",
+	"num_copies": "copies",
+}
+
+// inputError is a client-side validation error restated in terms of the
+// user's input. It unwraps to the *writesonic.RequestError so the exit code and
+// batch error_class stay "validation".
+type inputError struct {
+	msg string
+	err *writesonic.RequestError
+}
+
+func (e *inputError) Error() string { return e.msg }
+func (e *inputError) Unwrap() error { return e.err }
+
+// explainFlagError rewrites a client-side validation error from command to
+// point at the offending flag. Other errors are returned unchanged.
+func explainFlagError(command string, err error) error {
+	return explain(command, err, paramFlags, "--")
+}
+
+// explainColumnError is explainFlagError for a batch row, naming the column.
+func explainColumnError(command string, err error) error {
+	return explain(command, err, paramColumns, "field ")
+}
+
+func explain(command string, err error, params map[string]string, prefix string) error {
+	var re *writesonic.RequestError
+	if !errors.As(err, &re) {
+		return err
+	}
+	name := params[re.Param]
+	if re.Field != "" {
+		name = fieldSource(command, re.Field)
+	}
+	if name == "" {
+		return err
+	}
+	msg := prefix + name + " " + re.Message + "; --no-validate skips this check"
+	return &inputError{msg: strings.Replace(err.Error(), re.Error(), msg, 1), err: re}
+}
+
+// fieldSource returns the flag of command that fills the body field key.
+func fieldSource(command, key string) string {
+	spec, ok := batchSpecs[command]
+	if !ok {
+		return ""
+	}
+	for name, k := range spec.fields {
+		if k == key {
+			return name
+		}
+	}
+	return ""
+}
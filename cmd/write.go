@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
 )
 
 // write.go groups standalone writing utilities: paragraph, meta, conclusion
@@ -12,6 +13,8 @@ var (
 	metaBlogTitle         string
 	metaBlogDesc          string
 	conclusionTopic       string
+
+	paragraphInstructionsFile string
 )
 
 var writeCmd = &cobra.Command{
@@ -23,7 +26,8 @@ var writeParagraphCmd = &cobra.Command{
 	Use:   "paragraph",
 	Short: "Write a structured, persuasive paragraph",
 	Example: `  writesonic write paragraph --topic "Benefits of remote work"
-  writesonic write paragraph --topic "Why use AI for writing" --instructions "Focus on speed and quality"`,
+  writesonic write paragraph --topic "Why use AI for writing" --instructions "Focus on speed and quality"
+  writesonic write paragraph --topic "Onboarding" --instructions-file brief.md`,
 	RunE: runWriteParagraph,
 }
 
@@ -45,7 +49,8 @@ var writeConclusionCmd = &cobra.Command{
 
 func init() {
 	writeParagraphCmd.Flags().StringVar(&paragraphTopic, "topic", "", "Topic to write about (required)")
-	writeParagraphCmd.Flags().StringVar(&paragraphInstructions, "instructions", "", "Additional instructions (optional)")
+	writeParagraphCmd.Flags().StringVar(&paragraphInstructions, "instructions", "", "Additional instructions (optional; - for stdin)")
+	writeParagraphCmd.Flags().StringVar(&paragraphInstructionsFile, "instructions-file", "", "Read instructions from a file")
 	writeParagraphCmd.MarkFlagRequired("topic")
 
 	writeMetaCmd.Flags().StringVar(&metaBlogTitle, "title", "", "Blog post title (required)")
@@ -56,29 +61,34 @@ func init() {
 	writeConclusionCmd.Flags().StringVar(&conclusionTopic, "topic", "", "Article topic to conclude (required)")
 	writeConclusionCmd.MarkFlagRequired("topic")
 
+	addPickFlag(writeParagraphCmd, writeMetaCmd, writeConclusionCmd)
+	addOutputFlags(writeParagraphCmd, writeMetaCmd, writeConclusionCmd)
 	writeCmd.AddCommand(writeParagraphCmd, writeMetaCmd, writeConclusionCmd)
 	rootCmd.AddCommand(writeCmd)
 }
 
 func runWriteParagraph(cmd *cobra.Command, args []string) error {
-	body := map[string]interface{}{
-		"topic": paragraphTopic,
-	}
-	if paragraphInstructions != "" {
-		body["instructions"] = paragraphInstructions
+	instructions, err := textInput{
+		flag: "instructions", value: paragraphInstructions, file: paragraphInstructionsFile,
+	}.read()
+	if err != nil {
+		return err
 	}
-	return postAndPrint("/paragraph-writer", body)
+	return printCall(cmd.Context(), call(client.Paragraph, writesonic.ParagraphRequest{
+		Topic:        paragraphTopic,
+		Instructions: instructions,
+	}))
 }
 
 func runWriteMeta(cmd *cobra.Command, args []string) error {
-	return postAndPrint("/meta-blog", map[string]interface{}{
-		"blog_title":       metaBlogTitle,
-		"blog_description": metaBlogDesc,
-	})
+	return printCall(cmd.Context(), call(client.MetaBlog, writesonic.MetaBlogRequest{
+		Title:       metaBlogTitle,
+		Description: metaBlogDesc,
+	}))
 }
 
 func runWriteConclusion(cmd *cobra.Command, args []string) error {
-	return postAndPrint("/conclusion-writer", map[string]interface{}{
-		"topic": conclusionTopic,
-	})
+	return printCall(cmd.Context(), call(client.Conclusion, writesonic.ConclusionRequest{
+		Topic: conclusionTopic,
+	}))
 }
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Cache stores successful responses on disk, keyed by a fingerprint of the
+// request, so identical requests are not billed twice. It implements
+// writesonic.Cache.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// cacheEntry is the on-disk representation of one cached response.
+type cacheEntry struct {
+	Created  time.Time       `json:"created"`
+	Path     string          `json:"path"`
+	Response json.RawMessage `json:"response"`
+}
+
+// CacheStats summarizes the contents of a cache directory.
+type CacheStats struct {
+	Dir     string `json:"dir"`
+	Entries int    `json:"entries"`
+	Expired int    `json:"expired"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// NewCache returns a cache in dir whose entries expire after ttl.
+// A ttl of zero means entries never expire.
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl}
+}
+
+func (c *Cache) file(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func (c *Cache) expired(e cacheEntry, now time.Time) bool {
+	return c.ttl > 0 && now.Sub(e.Created) > c.ttl
+}
+
+// Get returns the cached response for key if present and not expired.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(c.file(key))
+	if err != nil {
+		return nil, false
+	}
+	var e cacheEntry
+	if json.Unmarshal(data, &e) != nil || c.expired(e, time.Now()) {
+		return nil, false
+	}
+	return e.Response, true
+}
+
+// Put stores a response under key, writing atomically.
+func (c *Cache) Put(key, path string, response []byte) error {
+	if !json.Valid(response) {
+		return fmt.Errorf("refusing to cache non-JSON response")
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	data, err := json.Marshal(cacheEntry{Created: time.Now(), Path: path, Response: response})
+	if err != nil {
+		return fmt.Errorf("marshal cache entry: %w", err)
+	}
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write cache file: %w", err)
+	}
+	return os.Rename(tmp.Name(), c.file(key))
+}
+
+// Stats scans the cache directory.
+func (c *Cache) Stats() (CacheStats, error) {
+	st := CacheStats{Dir: c.dir}
+	err := c.walk(func(path string, info os.FileInfo, e cacheEntry, ok bool) error {
+		st.Entries++
+		st.Bytes += info.Size()
+		if !ok || c.expired(e, time.Now()) {
+			st.Expired++
+		}
+		return nil
+	})
+	return st, err
+}
+
+// Clear removes cached entries. With expiredOnly set, only entries past the
+// TTL (or unreadable) are removed. It returns the number of files removed.
+func (c *Cache) Clear(expiredOnly bool) (int, error) {
+	removed := 0
+	err := c.walk(func(path string, info os.FileInfo, e cacheEntry, ok bool) error {
+		if expiredOnly && ok && !c.expired(e, time.Now()) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+func (c *Cache) walk(fn func(path string, info os.FileInfo, e cacheEntry, ok bool) error) error {
+	entries, err := os.ReadDir(c.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read cache dir: %w", err)
+	}
+	for _, de := range entries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), ".json") {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(c.dir, de.Name())
+		var e cacheEntry
+		data, err := os.ReadFile(path)
+		ok := err == nil && json.Unmarshal(data, &e) == nil
+		if err := fn(path, info, e, ok); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Cassettes are directories of recorded API interactions, one JSON file per
+// distinct request. A recorder saves every exchange it sees; a replayer
+// answers requests from the files without touching the network. Requests
+// match on the endpoint path relative to the client's base URL, the query and
+// the normalized JSON body, so the base URL (and thus a test server's random
+// port, or a gateway's path prefix) doesn't matter.
+
+// redacted replaces credentials in recorded headers.
+const redacted = "REDACTED"
+
+// sensitiveHeaders are recorded with their value replaced by redacted.
+var sensitiveHeaders = []string{"X-Api-Key", "Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+// volatileHeaders change on every call and are dropped so re-recording an
+// unchanged answer leaves the cassette file unchanged.
+var volatileHeaders = []string{"Date", "Content-Length", "Connection", "Keep-Alive"}
+
+// Interaction is one recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the request half of an Interaction.
+type RecordedRequest struct {
+	Method  string          `json:"method"`
+	Path    string          `json:"path"` // endpoint, relative to the base URL
+	Query   string          `json:"query,omitempty"`
+	Headers http.Header     `json:"headers,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response half of an Interaction.
+type RecordedResponse struct {
+	Status  int             `json:"status"`
+	Headers http.Header     `json:"headers,omitempty"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// CassetteMissError is returned by a strict replayer for a request that has
+// no recording.
+type CassetteMissError struct {
+	Method string
+	Path   string
+	File   string
+}
+
+// Retryable reports false: the answer won't appear by asking again, so the
+// client fails at once instead of backing off.
+func (e *CassetteMissError) Retryable() bool { return false }
+
+func (e *CassetteMissError) Error() string {
+	return fmt.Sprintf("no recorded interaction for %s %s (expected %s); record it with --record", e.Method, e.Path, e.File)
+}
+
+// NewRecorder returns a transport that sends requests through next and saves
+// each exchange to dir, with credentials redacted. baseURL is the client's
+// API root; paths are recorded relative to it. A later recording of the same
+// request replaces the earlier one, so only the final answer after retries is
+// kept.
+func NewRecorder(dir, baseURL string, next http.RoundTripper) http.RoundTripper {
+	return &recorder{dir: dir, base: basePath(baseURL), next: orDefault(next)}
+}
+
+// NewReplayer returns a transport that answers requests from the cassettes in
+// dir, matching paths relative to baseURL. Unmatched requests fail with
+// *CassetteMissError when strict is set and go to next otherwise.
+func NewReplayer(dir, baseURL string, strict bool, next http.RoundTripper) http.RoundTripper {
+	return &replayer{dir: dir, base: basePath(baseURL), strict: strict, next: orDefault(next)}
+}
+
+type recorder struct {
+	dir  string
+	base string
+	next http.RoundTripper
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	path := endpoint(req, r.base)
+	in := Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			Path:    path,
+			Query:   req.URL.Query().Encode(),
+			Headers: redactHeaders(req.Header),
+			Body:    rawJSON(body),
+		},
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: redactHeaders(resp.Header),
+			Body:    rawJSON(data),
+		},
+	}
+	if err := writeInteraction(r.dir, cassetteName(req, path, body), in); err != nil {
+		return nil, fmt.Errorf("record interaction: %w", err)
+	}
+	return resp, nil
+}
+
+type replayer struct {
+	dir    string
+	base   string
+	strict bool
+	next   http.RoundTripper
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	path := endpoint(req, r.base)
+	name := cassetteName(req, path, body)
+	data, err := os.ReadFile(filepath.Join(r.dir, name))
+	if os.IsNotExist(err) {
+		if r.strict {
+			return nil, &CassetteMissError{Method: req.Method, Path: path, File: filepath.Join(r.dir, name)}
+		}
+		return r.next.RoundTrip(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var in Interaction
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("read cassette %s: %w", name, err)
+	}
+	header := in.Response.Headers
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+		StatusCode:    in.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+		ContentLength: int64(len(in.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// basePath returns the path of baseURL without a trailing slash, e.g.
+// "/v2/business/content".
+func basePath(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(u.Path, "/")
+}
+
+// endpoint returns the request path relative to base, e.g. "/blog-ideas".
+// Paths outside base are used whole.
+func endpoint(req *http.Request, base string) string {
+	if rel := strings.TrimPrefix(req.URL.Path, base); base != "" && rel != req.URL.Path && (rel == "" || rel[0] == '/') {
+		return rel
+	}
+	return req.URL.Path
+}
+
+// cassetteName derives the file for a request from its endpoint path, query
+// and normalized body, e.g. "blog-ideas-3f9a0c1d2b4e5f60.json".
+func cassetteName(req *http.Request, path string, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s?%s\n", req.Method, path, req.URL.Query().Encode())
+	h.Write(normalizeJSON(body))
+	slug := strings.ReplaceAll(strings.Trim(path, "/"), "/", "_")
+	if slug == "" {
+		slug = "root"
+	}
+	return slug + "-" + hex.EncodeToString(h.Sum(nil))[:16] + ".json"
+}
+
+// normalizeJSON re-encodes a JSON document compactly with sorted object
+// keys, so formatting and key order don't affect matching.
+func normalizeJSON(data []byte) []byte {
+	var v interface{}
+	if len(data) == 0 || json.Unmarshal(data, &v) != nil {
+		return data
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// rawJSON returns data as a JSON value, quoting it if it isn't valid JSON.
+func rawJSON(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	if json.Valid(data) {
+		return json.RawMessage(data)
+	}
+	quoted, _ := json.Marshal(string(data))
+	return quoted
+}
+
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for _, k := range volatileHeaders {
+		out.Del(k)
+	}
+	for _, k := range sensitiveHeaders {
+		if _, ok := out[http.CanonicalHeaderKey(k)]; ok {
+			out.Set(k, redacted)
+		}
+	}
+	return out
+}
+
+// readBody reads the request body and restores it for the next transport.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func writeInteraction(dir, name string, in Interaction) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
+
+func orDefault(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		return http.DefaultTransport
+	}
+	return rt
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+func TestCassetteReplay(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/v2/business/content/blog-ideas" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"text":"recorded idea"}]`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	req := writesonic.BlogIdeasRequest{Topic: "remote work"}
+	opts := []writesonic.CallOption{writesonic.WithEngine("good"), writesonic.WithLanguage("en")}
+
+	base := srv.URL + "/v2/business/content"
+	rec := writesonic.NewClient("secret-key", writesonic.WithBaseURL(base),
+		writesonic.WithTransport(NewRecorder(dir, base, nil)))
+	if _, err := rec.BlogIdeas(ctx, req, opts...); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 || !strings.HasPrefix(filepath.Base(files[0]), "blog-ideas-") {
+		t.Fatalf("cassettes = %v, want one blog-ideas-*.json", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "secret-key") {
+		t.Error("cassette contains the API key")
+	}
+	if !strings.Contains(string(data), `"path": "/blog-ideas"`) {
+		t.Errorf("cassette path not relative to the base URL:\n%s", data)
+	}
+
+	// A different host and base path must still match, without the network.
+	other := "http://127.0.0.1:1/gateway/writesonic"
+	rep := writesonic.NewClient("other-key", writesonic.WithBaseURL(other),
+		writesonic.WithTransport(NewReplayer(dir, other, true, nil)))
+	got, err := rep.BlogIdeas(ctx, req, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Text != "recorded idea" {
+		t.Errorf("replayed %+v", got)
+	}
+	if calls != 1 {
+		t.Errorf("server called %d times, want 1", calls)
+	}
+}
+
+func TestCassetteMissIsNotRetried(t *testing.T) {
+	base := "http://127.0.0.1:1/v2/business/content"
+	var retries int
+	c := writesonic.NewClient("k", writesonic.WithBaseURL(base),
+		writesonic.WithTransport(NewReplayer(t.TempDir(), base, true, nil)),
+		writesonic.WithRetryNotify(func(int, time.Duration, error) { retries++ }))
+	_, err := c.CallToAction(context.Background(), writesonic.CallToActionRequest{ProductName: "Acme"})
+	var miss *CassetteMissError
+	if !errors.As(err, &miss) {
+		t.Fatalf("err = %v, want *CassetteMissError", err)
+	}
+	if miss.Path != "/call-to-action" {
+		t.Errorf("miss path = %q", miss.Path)
+	}
+	if retries != 0 {
+		t.Errorf("retried %d times", retries)
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	tests := []struct{ path, base, want string }{
+		{"/v2/business/content/blog-ideas", "/v2/business/content", "/blog-ideas"},
+		{"/blog-ideas", "", "/blog-ideas"},
+		{"/v2/business/contentx/pas", "/v2/business/content", "/v2/business/contentx/pas"},
+		{"/other/pas", "/v2/business/content", "/other/pas"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+		if got := endpoint(req, tt.base); got != tt.want {
+			t.Errorf("endpoint(%q, %q) = %q, want %q", tt.path, tt.base, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Job is a unit of work run by a Scheduler.
+type Job struct {
+	// Key selects the rate-limit bucket, typically the engine name.
+	Key string
+	// Do performs the request. It must honor ctx cancellation.
+	Do func(ctx context.Context) (interface{}, error)
+}
+
+// JobResult is the outcome of the job at Index in the submitted slice.
+type JobResult struct {
+	Index int
+	Value interface{}
+	Err   error
+}
+
+// Scheduler runs jobs with bounded concurrency, per-key client-side rate
+// limiting, and delivers results in submission order.
+type Scheduler struct {
+	concurrency int
+	limits      map[string]int // requests per minute; "" is the fallback
+	progress    io.Writer
+	live        bool
+
+	mu       sync.Mutex
+	limiters map[string]*RateLimiter
+}
+
+// SchedulerOption configures a Scheduler.
+type SchedulerOption func(*Scheduler)
+
+// WithRateLimit caps jobs with the given key to perMinute requests per minute.
+// An empty key sets the limit for keys without a specific entry.
+func WithRateLimit(key string, perMinute int) SchedulerOption {
+	return func(s *Scheduler) {
+		if perMinute > 0 {
+			s.limits[key] = perMinute
+		}
+	}
+}
+
+// WithProgress reports aggregate progress to w. When live is true the line is
+// redrawn in place (for terminals); otherwise a line is printed every 10%.
+func WithProgress(w io.Writer, live bool) SchedulerOption {
+	return func(s *Scheduler) {
+		s.progress = w
+		s.live = live
+	}
+}
+
+// NewScheduler creates a scheduler running at most concurrency jobs at once.
+func NewScheduler(concurrency int, opts ...SchedulerOption) *Scheduler {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	s := &Scheduler{
+		concurrency: concurrency,
+		limits:      map[string]int{},
+		limiters:    map[string]*RateLimiter{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// limiter returns the shared rate limiter for key, or nil if unlimited.
+func (s *Scheduler) limiter(key string) *RateLimiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l, ok := s.limiters[key]; ok {
+		return l
+	}
+	perMinute, ok := s.limits[key]
+	if !ok {
+		perMinute = s.limits[""]
+	}
+	var l *RateLimiter
+	if perMinute > 0 {
+		l = NewRateLimiter(perMinute)
+	}
+	s.limiters[key] = l
+	return l
+}
+
+// Run executes jobs and calls emit once per finished job, in submission
+// order, from the calling goroutine. If emit returns an error or ctx is
+// cancelled, no further jobs are started and in-flight jobs are cancelled.
+// Jobs that had already finished, including ones that finished ahead of an
+// earlier job that never did, are still passed to emit in index order before
+// Run returns, so no completed work is lost. Run returns the first emit
+// error, or an error if any job didn't run.
+func (s *Scheduler) Run(ctx context.Context, jobs []Job, emit func(JobResult) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	indexes := make(chan int)
+	results := make(chan JobResult, s.concurrency)
+	var wg sync.WaitGroup
+	for w := 0; w < s.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				job := jobs[i]
+				r := JobResult{Index: i}
+				if l := s.limiter(job.Key); l != nil {
+					r.Err = l.Wait(ctx)
+				}
+				if r.Err == nil {
+					r.Value, r.Err = job.Do(ctx)
+				}
+				results <- r
+			}
+		}()
+	}
+	go func() {
+		defer close(indexes)
+		for i := range jobs {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	p := newProgress(s.progress, s.live, len(jobs))
+	pending := map[int]JobResult{}
+	next, emitted := 0, 0
+	var emitErr error
+	send := func(r JobResult) {
+		emitted++
+		if err := emit(r); err != nil && emitErr == nil {
+			emitErr = err
+			cancel()
+		}
+	}
+	for r := range results {
+		p.record(r.Err)
+		pending[r.Index] = r
+		for emitErr == nil {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			send(r)
+		}
+	}
+	// Whatever finished behind a gap is emitted now rather than dropped.
+	rest := make([]int, 0, len(pending))
+	for i := range pending {
+		rest = append(rest, i)
+	}
+	sort.Ints(rest)
+	for _, i := range rest {
+		send(pending[i])
+	}
+	p.finish()
+	if emitErr != nil {
+		return emitErr
+	}
+	if emitted < len(jobs) {
+		return fmt.Errorf("scheduler stopped early: %w", ctx.Err())
+	}
+	return nil
+}
+
+// RateLimiter is a token bucket refilled continuously at a fixed rate.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // time to earn one token
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+// NewRateLimiter allows perMinute requests per minute with a burst of up to
+// one second's worth of requests (minimum 1).
+func NewRateLimiter(perMinute int) *RateLimiter {
+	burst := float64(perMinute) / 60
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		interval: time.Minute / time.Duration(perMinute),
+		burst:    burst,
+		tokens:   burst,
+		last:     time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) * float64(l.interval))
+		l.mu.Unlock()
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// progress prints aggregate job counts; a nil writer disables it.
+type progress struct {
+	w       io.Writer
+	live    bool
+	total   int
+	done    int
+	failed  int
+	start   time.Time
+	lastPct int
+}
+
+func newProgress(w io.Writer, live bool, total int) *progress {
+	return &progress{w: w, live: live, total: total, start: time.Now()}
+}
+
+func (p *progress) record(err error) {
+	p.done++
+	if err != nil {
+		p.failed++
+	}
+	if p.w == nil || p.total == 0 {
+		return
+	}
+	pct := p.done * 100 / p.total
+	if p.live {
+		fmt.Fprintf(p.w, "\r%s", p.line())
+	} else if pct/10 > p.lastPct/10 || p.done == p.total {
+		fmt.Fprintln(p.w, p.line())
+	}
+	p.lastPct = pct
+}
+
+func (p *progress) line() string {
+	elapsed := time.Since(p.start)
+	eta := "-"
+	if p.done > 0 && p.done < p.total {
+		remaining := elapsed / time.Duration(p.done) * time.Duration(p.total-p.done)
+		eta = remaining.Round(time.Second).String()
+	}
+	return fmt.Sprintf("progress: %d/%d (%d%%)  failed %d  elapsed %s  eta %s",
+		p.done, p.total, p.done*100/p.total, p.failed, elapsed.Round(time.Second), eta)
+}
+
+func (p *progress) finish() {
+	if p.w != nil && p.live && p.done > 0 {
+		fmt.Fprintln(p.w)
+	}
+}
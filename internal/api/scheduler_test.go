@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchedulerEmitsInOrder(t *testing.T) {
+	var jobs []Job
+	for i := 0; i < 20; i++ {
+		i := i
+		jobs = append(jobs, Job{Do: func(ctx context.Context) (interface{}, error) {
+			// Later jobs finish first.
+			time.Sleep(time.Duration(20-i) * time.Millisecond)
+			return i, nil
+		}})
+	}
+	var got []int
+	err := NewScheduler(5).Run(context.Background(), jobs, func(r JobResult) error {
+		if r.Err != nil {
+			t.Errorf("job %d: %v", r.Index, r.Err)
+		}
+		if r.Value.(int) != r.Index {
+			t.Errorf("job %d got value %v", r.Index, r.Value)
+		}
+		got = append(got, r.Index)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, idx := range got {
+		if idx != i {
+			t.Fatalf("emitted %v, want 0..19 in order", got)
+		}
+	}
+	if len(got) != len(jobs) {
+		t.Fatalf("emitted %d results, want %d", len(got), len(jobs))
+	}
+}
+
+// Jobs that finished ahead of a slow one are still emitted when the run is
+// stopped, so their (billed) results aren't lost.
+func TestSchedulerFlushesFinishedJobsOnStop(t *testing.T) {
+	started := make(chan struct{})
+	jobs := []Job{
+		{Do: func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}},
+		{Do: func(ctx context.Context) (interface{}, error) { return "b", nil }},
+		{Do: func(ctx context.Context) (interface{}, error) {
+			close(started)
+			return "c", nil
+		}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	var got []int
+	var failed []int
+	err := NewScheduler(3).Run(ctx, jobs, func(r JobResult) error {
+		got = append(got, r.Index)
+		if r.Err != nil {
+			failed = append(failed, r.Index)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []int{0, 1, 2}) || !reflect.DeepEqual(failed, []int{0}) {
+		t.Errorf("emitted %v with failures %v, want [0 1 2] with [0]", got, failed)
+	}
+}
+
+func TestSchedulerStopsOnEmitError(t *testing.T) {
+	stop := errors.New("stop")
+	release := make(chan struct{})
+	jobs := []Job{
+		{Do: func(ctx context.Context) (interface{}, error) {
+			<-release
+			return nil, nil
+		}},
+		{Do: func(ctx context.Context) (interface{}, error) {
+			defer close(release)
+			return nil, nil
+		}},
+		{Do: func(ctx context.Context) (interface{}, error) { return nil, nil }},
+	}
+	var got []int
+	err := NewScheduler(2).Run(context.Background(), jobs, func(r JobResult) error {
+		got = append(got, r.Index)
+		if r.Index == 0 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("err = %v, want the emit error", err)
+	}
+	// Job 1 finished before job 0 and must be flushed after the error.
+	if len(got) < 2 || got[0] != 0 || got[1] != 1 {
+		t.Errorf("emitted %v, want 0 then 1", got)
+	}
+}
+
+func TestSchedulerReportsUnstartedJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	jobs := make([]Job, 100)
+	for i := range jobs {
+		jobs[i] = Job{Do: func(ctx context.Context) (interface{}, error) { return nil, ctx.Err() }}
+	}
+	err := NewScheduler(1).Run(ctx, jobs, func(JobResult) error { return nil })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// TransportConfig describes network settings for talking to the API through
+// proxies, private CAs or mTLS gateways. The zero value yields a transport
+// equivalent to http.DefaultTransport.
+type TransportConfig struct {
+	// ProxyURL overrides the HTTP(S)_PROXY environment variables.
+	ProxyURL string
+	// CACertFile is a PEM bundle added to the system root pool.
+	CACertFile string
+	// ClientCertFile and ClientKeyFile enable mTLS when both are set.
+	ClientCertFile string
+	ClientKeyFile  string
+}
+
+// IsZero reports whether no custom transport settings are present.
+func (tc TransportConfig) IsZero() bool {
+	return tc == TransportConfig{}
+}
+
+// NewTransport builds an http.RoundTripper from tc.
+func NewTransport(tc TransportConfig) (http.RoundTripper, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if tc.ProxyURL != "" {
+		u, err := url.Parse(tc.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("parse proxy URL: %w", err)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	if tc.CACertFile == "" && tc.ClientCertFile == "" && tc.ClientKeyFile == "" {
+		return t, nil
+	}
+
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if tc.CACertFile != "" {
+		pem, err := os.ReadFile(tc.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", tc.CACertFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if tc.ClientCertFile != "" || tc.ClientKeyFile != "" {
+		if tc.ClientCertFile == "" || tc.ClientKeyFile == "" {
+			return nil, fmt.Errorf("client certificate and key must both be set for mTLS")
+		}
+		cert, err := tls.LoadX509KeyPair(tc.ClientCertFile, tc.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	t.TLSClientConfig = tlsCfg
+	return t, nil
+}
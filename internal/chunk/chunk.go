@@ -0,0 +1,351 @@
+// Package chunk splits Markdown or plain-text documents into pieces small
+// enough for the rewrite endpoints, leaving structural Markdown untouched.
+package chunk
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options controls how prose is chunked.
+type Options struct {
+	// MaxLen is the maximum length of a rewritable chunk, in characters.
+	MaxLen int
+	// MinLen is the shortest chunk worth sending; shorter prose is kept as is.
+	MinLen int
+}
+
+// Piece is a span of the document. Pieces with Rewrite set are prose that
+// may be sent to the API; all others must be reproduced verbatim.
+type Piece struct {
+	Text    string
+	Rewrite bool
+	// Sep is emitted after the piece when joining.
+	Sep string
+
+	tokens []string // protected spans, restored by Restore
+	orig   string   // source text, exactly as it appeared
+}
+
+// Split breaks text into pieces. Headings, fenced and indented code, lists,
+// blockquotes, tables, HTML, link definitions and front matter are kept
+// verbatim. Paragraphs longer than MaxLen are split at sentence boundaries,
+// and inline links, URLs and code spans are masked before rewriting.
+func Split(text string, opts Options) []Piece {
+	if opts.MaxLen <= 0 {
+		opts.MaxLen = 1000
+	}
+	blocks := splitBlocks(strings.ReplaceAll(text, "\r\n", "\n"))
+
+	var pieces []Piece
+	for i, b := range blocks {
+		sep := "\n\n"
+		if i == len(blocks)-1 {
+			sep = ""
+		}
+		if !b.prose {
+			pieces = append(pieces, Piece{Text: b.text, Sep: sep, orig: b.text})
+			continue
+		}
+		spans := splitProse(b.text, opts.MaxLen)
+		for j, sp := range spans {
+			part := b.text[sp.start:sp.end]
+			p := Piece{Text: part, Sep: sep, orig: part}
+			if j < len(spans)-1 {
+				p.Sep = b.text[sp.end:spans[j+1].start]
+			}
+			// Line breaks inside a paragraph are only wrapping, so the API
+			// gets it on one line; pieces that aren't sent keep their own.
+			masked, tokens := protect(strings.Join(strings.Fields(part), " "))
+			if utf8.RuneCountInString(masked) >= opts.MinLen {
+				p.Text, p.tokens, p.Rewrite = masked, tokens, true
+			}
+			pieces = append(pieces, p)
+		}
+	}
+	return pieces
+}
+
+// Restore puts protected spans back into a rewritten chunk. It fails if the
+// rewrite dropped or duplicated a placeholder, in which case the caller
+// should keep the original text.
+func (p Piece) Restore(rewritten string) (string, error) {
+	out := strings.TrimSpace(rewritten)
+	for i, tok := range p.tokens {
+		ph := placeholder(i)
+		if n := strings.Count(out, ph); n != 1 {
+			return "", fmt.Errorf("placeholder %s appears %d times in rewritten text", ph, n)
+		}
+		out = strings.Replace(out, ph, tok, 1)
+	}
+	return out, nil
+}
+
+// Original returns the piece's source text as it appeared in the document.
+func (p Piece) Original() string {
+	if p.orig != "" {
+		return p.orig
+	}
+	out, err := p.Restore(p.Text)
+	if err != nil {
+		return p.Text
+	}
+	return out
+}
+
+// Join reassembles pieces, using texts[i] in place of pieces[i].Text when
+// texts is non-nil and the entry is non-empty.
+func Join(pieces []Piece, texts []string) string {
+	var b strings.Builder
+	for i, p := range pieces {
+		t := p.Original()
+		if texts != nil && texts[i] != "" {
+			t = texts[i]
+		}
+		b.WriteString(t)
+		b.WriteString(p.Sep)
+	}
+	return b.String()
+}
+
+type block struct {
+	text  string
+	prose bool
+}
+
+var (
+	headingRe = regexp.MustCompile(`^\s{0,3}#{1,6}(\s|$)`)
+	fenceRe   = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+	listRe    = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+`)
+	hrRe      = regexp.MustCompile(`^\s{0,3}([-*_])(\s*([-*_]))\s*([-*_]\s*)+$`)
+	setextRe  = regexp.MustCompile(`^\s{0,3}(=+|-+)\s*$`)
+	linkDefRe = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s*\S`)
+)
+
+// structural reports whether a line belongs to Markdown that must be kept.
+func structural(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(line, "    "), strings.HasPrefix(line, "\t"):
+		return true // indented code
+	case headingRe.MatchString(line),
+		strings.HasPrefix(trimmed, ">"),
+		strings.HasPrefix(trimmed, "|"),
+		strings.HasPrefix(trimmed, "<"):
+		return true
+	}
+	return listRe.MatchString(line) || hrRe.MatchString(line) ||
+		setextRe.MatchString(line) || linkDefRe.MatchString(line)
+}
+
+// splitBlocks groups lines into blank-line separated blocks, keeping fenced
+// code and YAML/TOML front matter intact even when they contain blank lines.
+func splitBlocks(text string) []block {
+	lines := strings.Split(text, "\n")
+	var blocks []block
+	var cur []string
+	prose := true
+	flush := func() {
+		if len(cur) > 0 {
+			blocks = append(blocks, block{text: strings.Join(cur, "\n"), prose: prose})
+		}
+		cur, prose = nil, true
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		// Front matter: a leading --- or +++ line up to its closing twin.
+		if i == 0 && (strings.TrimSpace(line) == "---" || strings.TrimSpace(line) == "+++") {
+			delim := strings.TrimSpace(line)
+			if end := closing(lines, 1, func(l string) bool { return strings.TrimSpace(l) == delim }); end > 0 {
+				cur, prose = lines[:end+1], false
+				flush()
+				i = end
+				continue
+			}
+		}
+
+		if m := fenceRe.FindStringSubmatch(line); m != nil {
+			flush()
+			end := closing(lines, i+1, func(l string) bool { return strings.HasPrefix(strings.TrimSpace(l), m[1]) })
+			if end < 0 {
+				end = len(lines) - 1
+			}
+			cur, prose = lines[i:end+1], false
+			flush()
+			i = end
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if headingRe.MatchString(line) {
+			flush()
+			cur, prose = []string{line}, false
+			flush()
+			continue
+		}
+		if structural(line) {
+			prose = false
+		}
+		cur = append(cur, line)
+	}
+	flush()
+	return blocks
+}
+
+func closing(lines []string, from int, match func(string) bool) int {
+	for j := from; j < len(lines); j++ {
+		if match(lines[j]) {
+			return j
+		}
+	}
+	return -1
+}
+
+// span is a byte range [start, end) of a paragraph.
+type span struct{ start, end int }
+
+// splitProse splits a paragraph into spans of at most max runes, preferring
+// sentence boundaries and falling back to word boundaries. The text between
+// consecutive spans is the paragraph's own whitespace.
+func splitProse(para string, max int) []span {
+	whole := trimSpan(para, span{0, len(para)})
+	if utf8.RuneCountInString(para[whole.start:whole.end]) <= max {
+		return []span{whole}
+	}
+	var units []span
+	for _, s := range sentences(para) {
+		if utf8.RuneCountInString(para[s.start:s.end]) <= max {
+			units = append(units, s)
+			continue
+		}
+		units = append(units, words(para, s)...)
+	}
+	return pack(para, units, max)
+}
+
+// sentences splits text after ., ! or ? (plus closing quotes or brackets)
+// followed by whitespace, except inside links and code spans.
+func sentences(text string) []span {
+	protected := inlineRe.FindAllStringIndex(text, -1)
+	var out []span
+	add := func(s span) {
+		if s = trimSpan(text, s); s.end > s.start {
+			out = append(out, s)
+		}
+	}
+	start := 0
+	for i := 0; i < len(text); i++ {
+		if c := text[i]; c != '.' && c != '!' && c != '?' || inside(protected, i) {
+			continue
+		}
+		j := i + 1
+		for j < len(text) {
+			r, size := utf8.DecodeRuneInString(text[j:])
+			if !strings.ContainsRune(`"')]”’»`, r) {
+				break
+			}
+			j += size
+		}
+		if j < len(text) && (text[j] == ' ' || text[j] == '\n') {
+			add(span{start, j})
+			start = j + 1
+			i = j
+		}
+	}
+	add(span{start, len(text)})
+	return out
+}
+
+// words splits s at whitespace, but never inside a link or code span, so
+// each of those stays in one chunk and is masked whole.
+func words(text string, s span) []span {
+	sub := text[s.start:s.end]
+	protected := inlineRe.FindAllStringIndex(sub, -1)
+	var out []span
+	start := -1
+	for i, r := range sub {
+		if unicode.IsSpace(r) && !inside(protected, i) {
+			if start >= 0 {
+				out = append(out, span{s.start + start, s.start + i})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		out = append(out, span{s.start + start, s.end})
+	}
+	return out
+}
+
+// pack greedily merges consecutive units into spans of at most max runes.
+// A single unit longer than max is emitted on its own.
+func pack(text string, units []span, max int) []span {
+	var out []span
+	cur := span{-1, -1}
+	for _, u := range units {
+		switch {
+		case cur.start < 0:
+			cur = u
+		case utf8.RuneCountInString(text[cur.start:u.end]) <= max:
+			cur.end = u.end
+		default:
+			out = append(out, cur)
+			cur = u
+		}
+	}
+	if cur.start >= 0 {
+		out = append(out, cur)
+	}
+	return out
+}
+
+// trimSpan narrows s to exclude leading and trailing whitespace.
+func trimSpan(text string, s span) span {
+	t := text[s.start:s.end]
+	s.start += len(t) - len(strings.TrimLeftFunc(t, unicode.IsSpace))
+	s.end -= len(t) - len(strings.TrimRightFunc(t, unicode.IsSpace))
+	if s.end < s.start {
+		s.end = s.start
+	}
+	return s
+}
+
+// inside reports whether byte offset i falls within one of the matches.
+func inside(matches [][]int, i int) bool {
+	for _, m := range matches {
+		if i >= m[0] && i < m[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// inlineRe matches spans that must survive a rewrite unchanged: code spans,
+// images and links, autolinks, and bare URLs.
+var inlineRe = regexp.MustCompile("`[^`]+`|!?\\[[^\\]]*\\]\\([^)]*\\)|!?\\[[^\\]]*\\]\\[[^\\]]*\\]|<https?://[^>]+>|https?://[^\\s)]+")
+
+func placeholder(i int) string {
+	return fmt.Sprintf("⟦%d⟧", i+1)
+}
+
+// protect replaces inline spans with numbered placeholders.
+func protect(text string) (string, []string) {
+	var tokens []string
+	masked := inlineRe.ReplaceAllStringFunc(text, func(m string) string {
+		tokens = append(tokens, m)
+		return placeholder(len(tokens) - 1)
+	})
+	return masked, tokens
+}
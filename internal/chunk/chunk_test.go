@@ -0,0 +1,120 @@
+package chunk
+
+import (
+	"strings"
+	"testing"
+)
+
+const doc = `---
+title: Post
+---
+
+# Heading
+
+#hashtags are prose, not headings, and this paragraph is long enough to be
+sent for rewriting.
+
+- a list item
+- another one
+
+    indented  code
+
+` + "```go\nfunc main() {\n\n}\n```" + `
+
+Short
+lines
+stay.
+
+See [the  docs](https://example.com/docs) and ` + "`go  test`" + ` for details, then continue.`
+
+func TestSplitJoinRoundTrip(t *testing.T) {
+	pieces := Split(doc, Options{MaxLen: 60, MinLen: 20})
+	if got := Join(pieces, nil); got != doc {
+		t.Errorf("Join(Split(doc)) changed the document:\n%s", got)
+	}
+}
+
+func TestSplitStructure(t *testing.T) {
+	pieces := Split(doc, Options{MaxLen: 1000, MinLen: 20})
+	rewrite := map[string]bool{}
+	for _, p := range pieces {
+		rewrite[p.Original()] = p.Rewrite
+	}
+	for text, want := range map[string]bool{
+		"---\ntitle: Post\n---":          false,
+		"# Heading":                      false,
+		"- a list item\n- another one":   false,
+		"    indented  code":             false,
+		"```go\nfunc main() {\n\n}\n```": false,
+		"Short\nlines\nstay.":            false,
+		"#hashtags are prose, not headings, and this paragraph is long enough to be\nsent for rewriting.": true,
+	} {
+		got, ok := rewrite[text]
+		if !ok {
+			t.Errorf("no piece %q", text)
+		} else if got != want {
+			t.Errorf("piece %q: Rewrite = %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestSplitLongParagraph(t *testing.T) {
+	para := strings.Repeat("This sentence is filler. ", 10) +
+		"Then a [link with many words in its text](https://example.com/a-long-path) ends it."
+	pieces := Split(para, Options{MaxLen: 60, MinLen: 1})
+	if len(pieces) < 2 {
+		t.Fatalf("got %d pieces, want several", len(pieces))
+	}
+	for _, p := range pieces {
+		if n := len([]rune(p.Original())); n > 60 && !strings.Contains(p.Original(), "](") {
+			t.Errorf("piece of %d runes: %q", n, p.Original())
+		}
+		if strings.Count(p.Original(), "[") != strings.Count(p.Original(), "](") {
+			t.Errorf("link split across pieces: %q", p.Original())
+		}
+	}
+	if Join(pieces, nil) != para {
+		t.Error("round trip changed the paragraph")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	pieces := Split("Read [the guide](https://example.com) and run `make test` before you push.", Options{MinLen: 1})
+	if len(pieces) != 1 || !pieces[0].Rewrite {
+		t.Fatalf("pieces = %+v", pieces)
+	}
+	p := pieces[0]
+	if strings.Contains(p.Text, "example.com") || strings.Contains(p.Text, "make test") {
+		t.Fatalf("protected spans not masked: %q", p.Text)
+	}
+
+	got, err := p.Restore("Before pushing, run ⟦2⟧ and read ⟦1⟧.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Before pushing, run `make test` and read [the guide](https://example.com)."; got != want {
+		t.Errorf("Restore = %q, want %q", got, want)
+	}
+
+	if _, err := p.Restore("Read ⟦1⟧ before you push."); err == nil {
+		t.Error("Restore accepted a dropped placeholder")
+	}
+	if _, err := p.Restore("⟦1⟧ ⟦1⟧ ⟦2⟧"); err == nil {
+		t.Error("Restore accepted a duplicated placeholder")
+	}
+}
+
+func TestHeadingNeedsSpace(t *testing.T) {
+	for line, want := range map[string]bool{
+		"# Title":        true,
+		"###### Six":     true,
+		"####### Seven":  false,
+		"#":              true,
+		"#hashtag":       false,
+		"   ## Indented": true,
+	} {
+		if got := headingRe.MatchString(line); got != want {
+			t.Errorf("heading %q = %v, want %v", line, got, want)
+		}
+	}
+}
@@ -7,12 +7,123 @@ import (
 	"path/filepath"
 )
 
-// Config holds the persisted CLI configuration.
-type Config struct {
-	APIKey          string `json:"api_key"`
+// DefaultProfile names the implicit profile formed by the top-level settings.
+const DefaultProfile = "default"
+
+// Profile holds the per-account settings that can differ between workspaces.
+type Profile struct {
+	APIKey          string `json:"api_key,omitempty"`
 	DefaultEngine   string `json:"default_engine,omitempty"`
 	DefaultLanguage string `json:"default_language,omitempty"`
 	DefaultCopies   int    `json:"default_copies,omitempty"`
+	BaseURL         string `json:"base_url,omitempty"`
+}
+
+// Config holds the persisted CLI configuration. The embedded Profile is the
+// "default" profile; named profiles live in Profiles.
+type Config struct {
+	// Version is the file format version; see CurrentVersion.
+	Version int `json:"version,omitempty"`
+
+	Profile
+
+	// ActiveProfile is the profile used when neither --profile nor
+	// WRITESONIC_PROFILE is set. Empty means the default profile.
+	ActiveProfile string              `json:"active_profile,omitempty"`
+	Profiles      map[string]*Profile `json:"profiles,omitempty"`
+
+	// CredentialStore selects where API keys are kept: "config" (plaintext
+	// api_key fields, the default), "keyring" or "encrypted-file".
+	CredentialStore string `json:"credential_store,omitempty"`
+
+	// Retry settings for transient API failures. Delays are Go duration
+	// strings such as "500ms" or "2s". MaxRetries of nil means "use default".
+	MaxRetries    *int   `json:"max_retries,omitempty"`
+	RetryDelay    string `json:"retry_delay,omitempty"`
+	RetryMaxDelay string `json:"retry_max_delay,omitempty"`
+
+	// Timeout bounds each API request, as a Go duration string ("0" disables).
+	Timeout string `json:"timeout,omitempty"`
+
+	// Network overrides for proxies and private gateways. The base URL is
+	// part of Profile.
+	ProxyURL       string `json:"proxy_url,omitempty"`
+	CACertFile     string `json:"ca_cert,omitempty"`
+	ClientCertFile string `json:"client_cert,omitempty"`
+	ClientKeyFile  string `json:"client_key,omitempty"`
+
+	// Concurrency is the default number of parallel requests for bulk
+	// commands. RateLimits caps requests per minute by engine name; the
+	// "default" entry applies to engines without their own limit.
+	Concurrency int            `json:"concurrency,omitempty"`
+	RateLimits  map[string]int `json:"rate_limits,omitempty"`
+
+	// DisableHistory stops recording requests in the local history store.
+	DisableHistory bool `json:"disable_history,omitempty"`
+
+	// Cache enables the on-disk response cache by default; CacheTTL is a Go
+	// duration string (default 24h, "0" keeps entries forever).
+	Cache    bool   `json:"cache,omitempty"`
+	CacheTTL string `json:"cache_ttl,omitempty"`
+}
+
+// IsDefaultProfile reports whether name refers to the top-level settings.
+func IsDefaultProfile(name string) bool {
+	return name == "" || name == DefaultProfile
+}
+
+// LookupProfile returns the named profile, or the embedded default profile
+// for "" and "default".
+func (c *Config) LookupProfile(name string) (*Profile, error) {
+	if IsDefaultProfile(name) {
+		return &c.Profile, nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok || p == nil {
+		return nil, fmt.Errorf("profile %q not found — create it with: writesonic auth profile add %s", name, name)
+	}
+	return p, nil
+}
+
+// WithProfile returns a copy of c whose top-level settings are overlaid
+// with the non-empty fields of the named profile. The API key is never
+// inherited from the default profile, so a profile without its own key can't
+// bill another account by accident.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	p, err := c.LookupProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	out := *c
+	out.APIKey = p.APIKey
+	if p.DefaultEngine != "" {
+		out.DefaultEngine = p.DefaultEngine
+	}
+	if p.DefaultLanguage != "" {
+		out.DefaultLanguage = p.DefaultLanguage
+	}
+	if p.DefaultCopies > 0 {
+		out.DefaultCopies = p.DefaultCopies
+	}
+	if p.BaseURL != "" {
+		out.BaseURL = p.BaseURL
+	}
+	return &out, nil
+}
+
+// Dir returns the OS-specific config directory for writesonic-cli, where the
+// config file and local data such as history live.
+func Dir() (string, error) {
+	return configDir()
+}
+
+// CacheDir returns the OS-specific cache directory for writesonic-cli.
+func CacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("get cache dir: %w", err)
+	}
+	return filepath.Join(base, "writesonic"), nil
 }
 
 // configDir returns the OS-specific config directory for writesonic-cli.
@@ -34,6 +145,8 @@ func ConfigPath() (string, error) {
 }
 
 // Load reads the config from disk. Returns an empty Config if the file doesn't exist.
+// Files in an older format are migrated and rewritten, keeping a backup of
+// the original next to it.
 func Load() (*Config, error) {
 	path, err := ConfigPath()
 	if err != nil {
@@ -46,14 +159,38 @@ func Load() (*Config, error) {
 		}
 		return nil, fmt.Errorf("read config: %w", err)
 	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	from, err := migrate(values)
+	if err != nil {
+		return nil, err
+	}
+	migrated, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("migrate config: %w", err)
+	}
 	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	if err := json.Unmarshal(migrated, &cfg); err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
 	}
+	if from < CurrentVersion {
+		backup := fmt.Sprintf("%s.v%d.bak", path, from)
+		if _, err := os.Stat(backup); os.IsNotExist(err) {
+			if err := os.WriteFile(backup, data, 0600); err != nil {
+				return nil, fmt.Errorf("back up config: %w", err)
+			}
+		}
+		if err := cfg.Save(); err != nil {
+			return nil, fmt.Errorf("save migrated config: %w", err)
+		}
+	}
 	return &cfg, nil
 }
 
-// Save writes the config to disk with 0600 permissions.
+// Save writes the config to disk with 0600 permissions, stamped with the
+// current format version.
 func (c *Config) Save() error {
 	dir, err := configDir()
 	if err != nil {
@@ -63,6 +200,7 @@ func (c *Config) Save() error {
 		return fmt.Errorf("create config dir: %w", err)
 	}
 	path := filepath.Join(dir, "config.json")
+	c.Version = CurrentVersion
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal config: %w", err)
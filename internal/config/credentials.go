@@ -0,0 +1,104 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Credential store names accepted by the credential_store setting.
+const (
+	StoreConfig        = "config"         // plaintext api_key in config.json
+	StoreKeyring       = "keyring"        // OS keychain / Secret Service / Credential Manager
+	StoreEncryptedFile = "encrypted-file" // credentials.enc, sealed with a passphrase
+)
+
+// CredentialStores lists the supported backends.
+var CredentialStores = []string{StoreConfig, StoreKeyring, StoreEncryptedFile}
+
+// ErrNoCredential is returned by CredentialStore.Get when no key is stored
+// for a profile.
+var ErrNoCredential = errors.New("no stored credential")
+
+// CredentialStore keeps API keys outside the main config file, one per profile.
+type CredentialStore interface {
+	// Name returns the backend name as used in credential_store.
+	Name() string
+	// Get returns the key stored for profile, or ErrNoCredential.
+	Get(profile string) (string, error)
+	// Set stores key for profile, replacing any previous value.
+	Set(profile, key string) error
+	// Delete removes the key for profile. Deleting a missing key is not an error.
+	Delete(profile string) error
+}
+
+// PassphraseFunc supplies the passphrase for the encrypted-file store.
+// confirm is true when a new file is about to be created, so interactive
+// implementations can ask twice.
+type PassphraseFunc func(confirm bool) ([]byte, error)
+
+// CredentialBackend returns the store selected by credential_store. An empty
+// setting means StoreConfig, where keys live in the profile's api_key field.
+func (c *Config) CredentialBackend(passphrase PassphraseFunc) (CredentialStore, error) {
+	return c.credentialBackend(c.CredentialStore, passphrase)
+}
+
+// CredentialBackendNamed returns the named store regardless of the setting.
+func (c *Config) CredentialBackendNamed(name string, passphrase PassphraseFunc) (CredentialStore, error) {
+	return c.credentialBackend(name, passphrase)
+}
+
+func (c *Config) credentialBackend(name string, passphrase PassphraseFunc) (CredentialStore, error) {
+	switch name {
+	case "", StoreConfig:
+		return &configStore{cfg: c}, nil
+	case StoreKeyring:
+		return keyringStore{}, nil
+	case StoreEncryptedFile:
+		dir, err := configDir()
+		if err != nil {
+			return nil, err
+		}
+		return newEncryptedFileStore(dir, passphrase), nil
+	}
+	return nil, fmt.Errorf("unknown credential_store %q (use %s, %s or %s)",
+		name, StoreConfig, StoreKeyring, StoreEncryptedFile)
+}
+
+// configStore keeps keys in plaintext in config.json, as earlier versions did.
+type configStore struct {
+	cfg *Config
+}
+
+func (s *configStore) Name() string { return StoreConfig }
+
+func (s *configStore) Get(profile string) (string, error) {
+	p, err := s.cfg.LookupProfile(profile)
+	if err != nil {
+		return "", err
+	}
+	if p.APIKey == "" {
+		return "", ErrNoCredential
+	}
+	return p.APIKey, nil
+}
+
+func (s *configStore) Set(profile, key string) error {
+	p, err := s.cfg.LookupProfile(profile)
+	if err != nil {
+		return err
+	}
+	p.APIKey = key
+	return s.cfg.Save()
+}
+
+func (s *configStore) Delete(profile string) error {
+	p, err := s.cfg.LookupProfile(profile)
+	if err != nil {
+		return err
+	}
+	if p.APIKey == "" {
+		return nil
+	}
+	p.APIKey = ""
+	return s.cfg.Save()
+}
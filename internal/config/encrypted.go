@@ -0,0 +1,213 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters for deriving the file key from the passphrase.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// sealedFile is the on-disk format of credentials.enc. Data is the AES-256-GCM
+// encryption of a JSON object mapping profile names to API keys.
+type sealedFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	N       int    `json:"n"`
+	R       int    `json:"r"`
+	P       int    `json:"p"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// encryptedFileStore keeps keys in a passphrase-protected file next to
+// config.json, for machines without a usable keyring.
+type encryptedFileStore struct {
+	path       string
+	passphrase PassphraseFunc
+
+	// Filled on first use so the passphrase is asked for at most once.
+	key  []byte
+	salt []byte
+}
+
+func newEncryptedFileStore(dir string, passphrase PassphraseFunc) *encryptedFileStore {
+	return &encryptedFileStore{path: filepath.Join(dir, "credentials.enc"), passphrase: passphrase}
+}
+
+func (s *encryptedFileStore) Name() string { return StoreEncryptedFile }
+
+func (s *encryptedFileStore) Get(profile string) (string, error) {
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		return "", ErrNoCredential
+	}
+	keys, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	key, ok := keys[profileAccount(profile)]
+	if !ok || key == "" {
+		return "", ErrNoCredential
+	}
+	return key, nil
+}
+
+func (s *encryptedFileStore) Set(profile, key string) error {
+	keys, err := s.load()
+	if err != nil {
+		return err
+	}
+	keys[profileAccount(profile)] = key
+	return s.save(keys)
+}
+
+func (s *encryptedFileStore) Delete(profile string) error {
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	keys, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := keys[profileAccount(profile)]; !ok {
+		return nil
+	}
+	delete(keys, profileAccount(profile))
+	return s.save(keys)
+}
+
+// load decrypts the file, or returns an empty map if it doesn't exist yet.
+func (s *encryptedFileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read credentials: %w", err)
+	}
+	var f sealedFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse credentials: %w", err)
+	}
+	if f.Version != 1 || f.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported credentials file (version %d, kdf %q)", f.Version, f.KDF)
+	}
+	if s.key == nil {
+		pass, err := s.askPassphrase(false)
+		if err != nil {
+			return nil, err
+		}
+		if s.key, err = scrypt.Key(pass, f.Salt, f.N, f.R, f.P, scryptKeyLen); err != nil {
+			return nil, fmt.Errorf("derive key: %w", err)
+		}
+		s.salt = f.Salt
+	}
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, f.Nonce, f.Data, nil)
+	if err != nil {
+		s.key = nil
+		return nil, fmt.Errorf("decrypt credentials: wrong passphrase or corrupted file")
+	}
+	keys := map[string]string{}
+	if err := json.Unmarshal(plain, &keys); err != nil {
+		return nil, fmt.Errorf("parse credentials: %w", err)
+	}
+	return keys, nil
+}
+
+// save encrypts keys with a fresh nonce and writes the file atomically.
+func (s *encryptedFileStore) save(keys map[string]string) error {
+	if s.key == nil {
+		pass, err := s.askPassphrase(true)
+		if err != nil {
+			return err
+		}
+		s.salt = make([]byte, 16)
+		if _, err := rand.Read(s.salt); err != nil {
+			return fmt.Errorf("generate salt: %w", err)
+		}
+		if s.key, err = scrypt.Key(pass, s.salt, scryptN, scryptR, scryptP, scryptKeyLen); err != nil {
+			return fmt.Errorf("derive key: %w", err)
+		}
+	}
+	plain, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("marshal credentials: %w", err)
+	}
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generate nonce: %w", err)
+	}
+	data, err := json.MarshalIndent(sealedFile{
+		Version: 1, KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP,
+		Salt: s.salt, Nonce: nonce, Data: gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal credentials: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".credentials-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write credentials: %w", err)
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("set permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write credentials: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *encryptedFileStore) askPassphrase(confirm bool) ([]byte, error) {
+	if s.passphrase == nil {
+		return nil, fmt.Errorf("a passphrase is required to unlock %s", s.path)
+	}
+	pass, err := s.passphrase(confirm)
+	if err != nil {
+		return nil, err
+	}
+	if len(pass) == 0 {
+		return nil, fmt.Errorf("empty passphrase")
+	}
+	return pass, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("init cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
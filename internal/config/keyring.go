@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the service name under which keys are filed in the OS
+// keychain (macOS), Secret Service over D-Bus (Linux) or Credential Manager
+// (Windows). The account is the profile name.
+const keyringService = "writesonic-cli"
+
+type keyringStore struct{}
+
+func (keyringStore) Name() string { return StoreKeyring }
+
+func (keyringStore) Get(profile string) (string, error) {
+	key, err := keyring.Get(keyringService, profileAccount(profile))
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNoCredential
+	}
+	if err != nil {
+		return "", fmt.Errorf("read keyring: %w", err)
+	}
+	return key, nil
+}
+
+func (keyringStore) Set(profile, key string) error {
+	if err := keyring.Set(keyringService, profileAccount(profile), key); err != nil {
+		return fmt.Errorf("write keyring: %w (on machines without a keyring, use the encrypted-file store)", err)
+	}
+	return nil
+}
+
+func (keyringStore) Delete(profile string) error {
+	err := keyring.Delete(keyringService, profileAccount(profile))
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("delete from keyring: %w", err)
+	}
+	return nil
+}
+
+// profileAccount normalizes "" to the default profile name.
+func profileAccount(profile string) string {
+	if IsDefaultProfile(profile) {
+		return DefaultProfile
+	}
+	return profile
+}
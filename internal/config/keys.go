@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+)
+
+// Get returns the value stored at a dotted key and whether it is set.
+func (c *Config) Get(key string) (interface{}, bool, error) {
+	_, path, err := LookupSetting(key)
+	if err != nil {
+		return nil, false, err
+	}
+	values, err := c.Values()
+	if err != nil {
+		return nil, false, err
+	}
+	var cur interface{} = values
+	for _, p := range path {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false, nil
+		}
+		if cur, ok = m[p]; !ok {
+			return nil, false, nil
+		}
+	}
+	return cur, true, nil
+}
+
+// Set parses raw for the setting at a dotted key, validates it and stores it
+// in c. Secrets are refused; they belong in the credential store.
+func (c *Config) Set(key, raw string) error {
+	s, path, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+	if s.Secret {
+		return fmt.Errorf("%s can't be set here; use: writesonic auth set-key", key)
+	}
+	v, err := s.Parse(raw)
+	if err != nil {
+		return err
+	}
+	switch {
+	case path[0] == "profiles":
+		if IsDefaultProfile(path[1]) {
+			return fmt.Errorf("the default profile uses the top-level keys; set %s instead", path[2])
+		}
+		if _, err := c.LookupProfile(path[1]); err != nil {
+			return err
+		}
+	case key == "active_profile":
+		if _, err := c.LookupProfile(raw); err != nil {
+			return err
+		}
+	}
+	return c.edit(func(values map[string]interface{}) {
+		m := values
+		for _, p := range path[:len(path)-1] {
+			next, ok := m[p].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				m[p] = next
+			}
+			m = next
+		}
+		m[path[len(path)-1]] = v
+	})
+}
+
+// Unset removes the setting at a dotted key from c, so the next layer's
+// value (or the built-in default) applies. It reports whether it was set.
+func (c *Config) Unset(key string) (bool, error) {
+	_, path, err := LookupSetting(key)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	err = c.edit(func(values map[string]interface{}) {
+		m := values
+		for _, p := range path[:len(path)-1] {
+			next, ok := m[p].(map[string]interface{})
+			if !ok {
+				return
+			}
+			m = next
+		}
+		if _, ok := m[path[len(path)-1]]; ok {
+			delete(m, path[len(path)-1])
+			found = true
+		}
+	})
+	return found, err
+}
+
+// edit applies fn to c's values and replaces c with the result. Values are
+// validated by the caller, so an unrelated bad setting doesn't block a fix.
+func (c *Config) edit(fn func(map[string]interface{})) error {
+	values, err := c.Values()
+	if err != nil {
+		return err
+	}
+	fn(values)
+	var out Config
+	if err := decodeStrict(values, &out); err != nil {
+		return err
+	}
+	*c = out
+	return nil
+}
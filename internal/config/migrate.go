@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CurrentVersion is the config file format written by this build. Files
+// without a "version" field are version 1.
+const CurrentVersion = 2
+
+// migrations[i] upgrades a raw config from version i+1 to i+2.
+var migrations = []func(map[string]interface{}){
+	migrateV1,
+}
+
+// migrate upgrades values in place to CurrentVersion and reports the version
+// it started from.
+func migrate(values map[string]interface{}) (int, error) {
+	version := 1
+	if v, ok := values["version"].(float64); ok {
+		version = int(v)
+	}
+	if version > CurrentVersion {
+		return version, fmt.Errorf("config file version %d is newer than this build supports (%d); please update writesonic", version, CurrentVersion)
+	}
+	for v := version; v < CurrentVersion; v++ {
+		migrations[v-1](values)
+	}
+	values["version"] = CurrentVersion
+	return version, nil
+}
+
+// migrateV1 normalizes hand-edited version 1 files, which were never
+// validated: engine and language names are lowercased, and durations given
+// as bare numbers are read as seconds.
+func migrateV1(values map[string]interface{}) {
+	normalizeProfile(values)
+	for _, key := range []string{"retry_delay", "retry_max_delay", "timeout", "cache_ttl"} {
+		if n, ok := values[key].(float64); ok {
+			values[key] = fmt.Sprintf("%gs", n)
+		}
+	}
+	if profiles, ok := values["profiles"].(map[string]interface{}); ok {
+		for name, p := range profiles {
+			m, ok := p.(map[string]interface{})
+			if !ok {
+				delete(profiles, name)
+				continue
+			}
+			normalizeProfile(m)
+		}
+	}
+}
+
+func normalizeProfile(values map[string]interface{}) {
+	for _, key := range []string{"default_engine", "default_language"} {
+		if s, ok := values[key].(string); ok {
+			values[key] = strings.ToLower(strings.TrimSpace(s))
+		}
+	}
+}
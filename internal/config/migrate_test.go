@@ -0,0 +1,98 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateV1(t *testing.T) {
+	values := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(`{
+		"default_engine": " Premium ",
+		"default_language": "FR",
+		"retry_delay": 2,
+		"timeout": 90,
+		"profiles": {
+			"acme": {"default_engine": "GOOD"},
+			"broken": "not an object"
+		}
+	}`), &values); err != nil {
+		t.Fatal(err)
+	}
+	from, err := migrate(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != 1 {
+		t.Errorf("from = %d, want 1", from)
+	}
+	for key, want := range map[string]interface{}{
+		"version":          CurrentVersion,
+		"default_engine":   "premium",
+		"default_language": "fr",
+		"retry_delay":      "2s",
+		"timeout":          "90s",
+	} {
+		if values[key] != want {
+			t.Errorf("%s = %v, want %v", key, values[key], want)
+		}
+	}
+	profiles := values["profiles"].(map[string]interface{})
+	if _, ok := profiles["broken"]; ok {
+		t.Error("malformed profile was kept")
+	}
+	if got := profiles["acme"].(map[string]interface{})["default_engine"]; got != "good" {
+		t.Errorf("acme engine = %v", got)
+	}
+}
+
+func TestMigrateRejectsNewerVersion(t *testing.T) {
+	_, err := migrate(map[string]interface{}{"version": float64(CurrentVersion + 1)})
+	if err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("err = %v, want a newer-version error", err)
+	}
+}
+
+func TestLoadMigratesAndBacksUp(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	v1 := []byte(`{"default_engine":"Economy","cache_ttl":3600}`)
+	if err := os.WriteFile(path, v1, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DefaultEngine != "economy" || cfg.CacheTTL != "3600s" || cfg.Version != CurrentVersion {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	backup, err := os.ReadFile(path + ".v1.bak")
+	if err != nil || string(backup) != string(v1) {
+		t.Errorf("backup = %q, %v; want the original file", backup, err)
+	}
+	var saved struct{ Version int }
+	data, _ := os.ReadFile(path)
+	if json.Unmarshal(data, &saved); saved.Version != CurrentVersion {
+		t.Errorf("migrated file not rewritten:\n%s", data)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	cfg, err := Load()
+	if err != nil || cfg == nil {
+		t.Fatalf("Load() = %v, %v; want an empty config", cfg, err)
+	}
+}
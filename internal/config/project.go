@@ -0,0 +1,201 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileNames are the project-local config files looked for in each
+// directory, in order of preference.
+var ProjectFileNames = []string{".writesonic.yaml", ".writesonic.yml", ".writesonic.json"}
+
+// projectForbidden lists settings a project file may not set: a checked-out
+// repository must not be able to read the user's key or send it elsewhere.
+var projectForbidden = []string{
+	"api_key", "credential_store", "profiles",
+	"base_url", "proxy_url", "ca_cert", "client_cert", "client_key",
+}
+
+// Project is a project-local config file. Values holds only the settings
+// the file sets explicitly, so "cache: false" can override a user default.
+type Project struct {
+	Path   string
+	Values map[string]interface{}
+}
+
+// FindProject walks up from dir and returns the first project config file
+// found, or "" if there is none.
+func FindProject(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, name := range ProjectFileNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProject reads and validates a project config file (YAML or JSON).
+func LoadProject(path string) (*Project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read project config: %w", err)
+	}
+	values := map[string]interface{}{}
+	if strings.HasSuffix(path, ".json") {
+		err = json.Unmarshal(data, &values)
+	} else {
+		err = yaml.Unmarshal(data, &values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	for _, key := range projectForbidden {
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("%s: %q can only be set in the user config or with flags", path, key)
+		}
+	}
+	// Decode strictly once to catch unknown keys and wrong types early.
+	if err := decodeStrict(values, &Config{}); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := ValidateValues(values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &Project{Path: path, Values: values}, nil
+}
+
+// WithOverlay returns a copy of c with values applied on top. Nested objects
+// such as rate_limits are merged key by key.
+func (c *Config) WithOverlay(values map[string]interface{}) (*Config, error) {
+	base, err := c.Values()
+	if err != nil {
+		return nil, err
+	}
+	mergeValues(base, values)
+	var out Config
+	if err := decodeStrict(base, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Values returns c as a map keyed by setting name, omitting unset fields.
+func (c *Config) Values() (map[string]interface{}, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	return values, nil
+}
+
+// Flatten turns nested values into dotted keys ("rate_limits.premium")
+// with their values formatted for display.
+func Flatten(values map[string]interface{}) map[string]string {
+	out := map[string]string{}
+	flatten("", values, out)
+	return out
+}
+
+func flatten(prefix string, values map[string]interface{}, out map[string]string) {
+	for k, v := range values {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch t := v.(type) {
+		case map[string]interface{}:
+			flatten(key, t, out)
+		case nil:
+		default:
+			out[key] = FormatValue(t)
+		}
+	}
+}
+
+// FormatValue renders a setting value the way it would be typed.
+func FormatValue(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		if t == float64(int64(t)) {
+			return fmt.Sprintf("%d", int64(t))
+		}
+	case []interface{}:
+		parts := make([]string, len(t))
+		for i, item := range t {
+			parts[i] = FormatValue(item)
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// SortedKeys returns the keys of m in order.
+func SortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mergeValues(dst, src map[string]interface{}) {
+	for k, v := range src {
+		sub, isMap := v.(map[string]interface{})
+		existing, hasMap := dst[k].(map[string]interface{})
+		if isMap && hasMap {
+			mergeValues(existing, sub)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+// decodeStrict converts values into dst via JSON, rejecting unknown keys.
+func decodeStrict(values map[string]interface{}, dst *Config) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("%s: expected %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+		}
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return fmt.Errorf("unknown setting %s", field)
+		}
+		return err
+	}
+	return nil
+}
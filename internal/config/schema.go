@@ -0,0 +1,313 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+// Engines, Languages and MaxCopies mirror the limits the API client
+// enforces, so config values and request validation agree.
+var (
+	Engines   = writesonic.Engines
+	Languages = writesonic.Languages
+)
+
+// MaxCopies is the largest num_copies the API accepts.
+const MaxCopies = writesonic.MaxCopies
+
+// Kind is the type of a setting's value.
+type Kind string
+
+const (
+	KindString   Kind = "string"
+	KindInt      Kind = "int"
+	KindBool     Kind = "bool"
+	KindDuration Kind = "duration"
+	KindURL      Kind = "url"
+	KindEnum     Kind = "enum"
+)
+
+// Setting describes one config key: its type, allowed values and help text.
+type Setting struct {
+	Key         string
+	Kind        Kind
+	Description string
+	Values      []string // allowed values for KindEnum
+	Min, Max    int      // inclusive bounds for KindInt; Max 0 means unbounded
+	// Secret settings are masked when displayed and can't be set with "config set".
+	Secret bool
+}
+
+// profileSchema covers the settings that can also appear under profiles.<name>.
+var profileSchema = []Setting{
+	{Key: "api_key", Kind: KindString, Secret: true, Description: "API key (set with: writesonic auth set-key)"},
+	{Key: "default_engine", Kind: KindEnum, Values: Engines, Description: "Default engine"},
+	{Key: "default_language", Kind: KindEnum, Values: Languages, Description: "Default language code"},
+	{Key: "default_copies", Kind: KindInt, Min: 1, Max: MaxCopies, Description: "Default number of copies"},
+	{Key: "base_url", Kind: KindURL, Description: "API base URL"},
+}
+
+// Schema lists every top-level setting in config.json.
+var Schema = append(append([]Setting{}, profileSchema...),
+	Setting{Key: "active_profile", Kind: KindString, Description: "Profile used when --profile and WRITESONIC_PROFILE are unset"},
+	Setting{Key: "credential_store", Kind: KindEnum, Values: CredentialStores, Description: "Where API keys are stored"},
+	Setting{Key: "max_retries", Kind: KindInt, Min: 0, Max: 20, Description: "Retries for transient API failures"},
+	Setting{Key: "retry_delay", Kind: KindDuration, Description: "Initial retry backoff"},
+	Setting{Key: "retry_max_delay", Kind: KindDuration, Description: "Maximum delay between retries"},
+	Setting{Key: "timeout", Kind: KindDuration, Description: "Per-request timeout (0 disables)"},
+	Setting{Key: "proxy_url", Kind: KindURL, Description: "HTTP(S) proxy URL"},
+	Setting{Key: "ca_cert", Kind: KindString, Description: "PEM file with extra CA certificates"},
+	Setting{Key: "client_cert", Kind: KindString, Description: "PEM client certificate for mTLS"},
+	Setting{Key: "client_key", Kind: KindString, Description: "PEM client private key for mTLS"},
+	Setting{Key: "concurrency", Kind: KindInt, Min: 1, Max: 64, Description: "Parallel requests for bulk commands"},
+	Setting{Key: "rate_limits.<engine>", Kind: KindInt, Min: 1, Description: `Requests per minute for an engine, or "default"`},
+	Setting{Key: "disable_history", Kind: KindBool, Description: "Don't record requests in the local history"},
+	Setting{Key: "cache", Kind: KindBool, Description: "Enable the response cache by default"},
+	Setting{Key: "cache_ttl", Kind: KindDuration, Description: "How long cached responses stay valid (0 = forever)"},
+	Setting{Key: "profiles.<name>.<setting>", Kind: KindString, Description: "Per-profile api_key, default_engine, default_language, default_copies, base_url"},
+)
+
+// LookupSetting resolves a dotted key such as "default_engine",
+// "rate_limits.premium" or "profiles.acme.default_language" to its schema
+// entry and the path of map keys it addresses.
+func LookupSetting(key string) (*Setting, []string, error) {
+	parts := strings.Split(key, ".")
+	for _, p := range parts {
+		if p == "" {
+			return nil, nil, fmt.Errorf("invalid key %q", key)
+		}
+	}
+	switch parts[0] {
+	case "rate_limits":
+		if len(parts) != 2 {
+			return nil, nil, fmt.Errorf("use rate_limits.<engine>, e.g. rate_limits.premium")
+		}
+		if parts[1] != "default" && !contains(Engines, parts[1]) {
+			return nil, nil, fmt.Errorf("unknown engine %q in %s (use %s or default)", parts[1], key, strings.Join(Engines, ", "))
+		}
+		s := findSetting(Schema, "rate_limits.<engine>")
+		return s, parts, nil
+	case "profiles":
+		if len(parts) != 3 {
+			return nil, nil, fmt.Errorf("use profiles.<name>.<setting>, e.g. profiles.acme.default_engine")
+		}
+		s := findSetting(profileSchema, parts[2])
+		if s == nil {
+			return nil, nil, fmt.Errorf("unknown profile setting %q (use %s)", parts[2], strings.Join(settingKeys(profileSchema), ", "))
+		}
+		return s, parts, nil
+	}
+	if len(parts) == 1 {
+		if s := findSetting(Schema, key); s != nil {
+			return s, parts, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("unknown setting %q — run \"writesonic config list\" for the available keys", key)
+}
+
+// Parse converts a command-line string into a validated value of the
+// setting's type, ready to be stored.
+func (s *Setting) Parse(raw string) (interface{}, error) {
+	raw = strings.TrimSpace(raw)
+	switch s.Kind {
+	case KindInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a whole number, got %q", s.Key, raw)
+		}
+		return n, s.checkRange(n)
+	case KindBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false, got %q", s.Key, raw)
+		}
+		return b, nil
+	case KindEnum:
+		v := strings.ToLower(raw)
+		if !contains(s.Values, v) {
+			return nil, fmt.Errorf("invalid %s %q (use one of: %s)", s.Key, raw, strings.Join(s.Values, ", "))
+		}
+		return v, nil
+	}
+	return raw, s.checkString(raw)
+}
+
+// Check validates a decoded value, as found in a config file.
+func (s *Setting) Check(v interface{}) error {
+	switch s.Kind {
+	case KindInt:
+		var n int
+		switch t := v.(type) {
+		case int:
+			n = t
+		case float64:
+			if t != float64(int(t)) {
+				return fmt.Errorf("%s must be a whole number, got %v", s.Key, v)
+			}
+			n = int(t)
+		default:
+			return fmt.Errorf("%s must be a whole number, got %v", s.Key, v)
+		}
+		return s.checkRange(n)
+	case KindBool:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s must be true or false, got %v", s.Key, v)
+		}
+		return nil
+	}
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("%s must be a string, got %v", s.Key, v)
+	}
+	if s.Kind == KindEnum {
+		if !contains(s.Values, str) {
+			return fmt.Errorf("invalid %s %q (use one of: %s)", s.Key, str, strings.Join(s.Values, ", "))
+		}
+		return nil
+	}
+	return s.checkString(str)
+}
+
+func (s *Setting) checkRange(n int) error {
+	if n < s.Min || (s.Max > 0 && n > s.Max) {
+		if s.Max > 0 {
+			return fmt.Errorf("%s must be between %d and %d, got %d", s.Key, s.Min, s.Max, n)
+		}
+		return fmt.Errorf("%s must be at least %d, got %d", s.Key, s.Min, n)
+	}
+	return nil
+}
+
+func (s *Setting) checkString(v string) error {
+	switch s.Kind {
+	case KindDuration:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%s must be a duration like 30s or 5m, got %q", s.Key, v)
+		}
+		if d < 0 {
+			return fmt.Errorf("%s must not be negative", s.Key)
+		}
+	case KindURL:
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s must be an http(s) URL, got %q", s.Key, v)
+		}
+	}
+	return nil
+}
+
+// Validate checks every setting in c against the schema.
+func (c *Config) Validate() error {
+	values, err := c.Values()
+	if err != nil {
+		return err
+	}
+	return ValidateUserValues(values)
+}
+
+// ValidateUserValues is ValidateValues plus the checks that only make sense
+// for the user config, such as active_profile naming an existing profile.
+func ValidateUserValues(values map[string]interface{}) error {
+	if err := ValidateValues(values); err != nil {
+		return err
+	}
+	if name, ok := values["active_profile"].(string); ok && !IsDefaultProfile(name) {
+		profiles, _ := values["profiles"].(map[string]interface{})
+		if _, found := profiles[name]; !found {
+			return fmt.Errorf("invalid config:\n  active_profile %q does not exist", name)
+		}
+	}
+	return nil
+}
+
+// ValidateValues checks a raw settings map, as read from a config file,
+// against the schema. Errors name the offending dotted key.
+func ValidateValues(values map[string]interface{}) error {
+	var errs []string
+	for _, k := range sortedMapKeys(values) {
+		v := values[k]
+		switch k {
+		case "version":
+			continue
+		case "rate_limits", "profiles":
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				errs = append(errs, fmt.Sprintf("%s must be an object", k))
+				continue
+			}
+			for _, sub := range sortedMapKeys(m) {
+				if k == "rate_limits" {
+					errs = append(errs, checkKey(k+"."+sub, m[sub])...)
+					continue
+				}
+				p, ok := m[sub].(map[string]interface{})
+				if !ok {
+					errs = append(errs, fmt.Sprintf("profiles.%s must be an object", sub))
+					continue
+				}
+				for _, pk := range sortedMapKeys(p) {
+					errs = append(errs, checkKey("profiles."+sub+"."+pk, p[pk])...)
+				}
+			}
+		default:
+			errs = append(errs, checkKey(k, v)...)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+func checkKey(key string, v interface{}) []string {
+	s, _, err := LookupSetting(key)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if err := s.Check(v); err != nil {
+		return []string{strings.Replace(err.Error(), s.Key, key, 1)}
+	}
+	return nil
+}
+
+func findSetting(list []Setting, key string) *Setting {
+	for i := range list {
+		if list[i].Key == key {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+func settingKeys(list []Setting) []string {
+	keys := make([]string, len(list))
+	for i, s := range list {
+		keys[i] = s.Key
+	}
+	return keys
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,303 @@
+// Package history persists generation requests and their responses locally
+// so results can be searched, exported and replayed later.
+package history
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is one recorded request/response pair.
+type Entry struct {
+	ID       string                 `json:"id"`
+	Time     time.Time              `json:"time"`
+	Command  string                 `json:"command"`
+	Endpoint string                 `json:"endpoint"`
+	Engine   string                 `json:"engine,omitempty"`
+	Language string                 `json:"language,omitempty"`
+	Copies   int                    `json:"copies,omitempty"`
+	Body     map[string]interface{} `json:"body,omitempty"`
+	// ReplayedFrom is the ID of the entry this one replayed, if any.
+	ReplayedFrom string `json:"replayed_from,omitempty"`
+	// Results is the raw response: a Result or LandingPage list.
+	Results json.RawMessage `json:"results"`
+}
+
+// Texts returns every string value in the entry's results.
+func (e Entry) Texts() []string {
+	var v interface{}
+	if json.Unmarshal(e.Results, &v) != nil {
+		return nil
+	}
+	var out []string
+	collectStrings(v, &out)
+	return out
+}
+
+// Preview returns a representative snippet: the text or title of the first
+// result, falling back to any string in the response.
+func (e Entry) Preview() string {
+	var list []map[string]interface{}
+	if json.Unmarshal(e.Results, &list) == nil && len(list) > 0 {
+		for _, key := range []string{"text", "title"} {
+			if s, ok := list[0][key].(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+	if texts := e.Texts(); len(texts) > 0 {
+		return texts[0]
+	}
+	return ""
+}
+
+func collectStrings(v interface{}, out *[]string) {
+	switch t := v.(type) {
+	case string:
+		if t != "" {
+			*out = append(*out, t)
+		}
+	case []interface{}:
+		for _, item := range t {
+			collectStrings(item, out)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectStrings(t[k], out)
+		}
+	}
+}
+
+// NewID returns a time-ordered, reasonably unique entry ID.
+func NewID(t time.Time) string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return t.UTC().Format("20060102T150405") + "-" + hex.EncodeToString(b)
+}
+
+// Store is an append-only JSONL file of entries.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Open returns the store at path. The file is created on first Append.
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the store's file path.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append adds an entry, filling in ID and Time if they are unset.
+func (s *Store) Append(e *Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.ID == "" {
+		e.ID = NewID(e.Time)
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal history entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("create history dir: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("open history: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+	return nil
+}
+
+// All returns every entry, oldest first. Unparseable lines are skipped.
+func (s *Store) All() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) == nil && e.ID != "" {
+			entries = append(entries, e)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	return entries, nil
+}
+
+// Find returns the entry whose ID equals or uniquely starts with id.
+func (s *Store) Find(id string) (*Entry, error) {
+	entries, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	var match *Entry
+	for i := range entries {
+		if entries[i].ID == id {
+			return &entries[i], nil
+		}
+		if strings.HasPrefix(entries[i].ID, id) {
+			if match != nil {
+				return nil, fmt.Errorf("history ID %q is ambiguous", id)
+			}
+			match = &entries[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("no history entry %q", id)
+	}
+	return match, nil
+}
+
+// Replace atomically rewrites the store with entries.
+func (s *Store) Replace(entries []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("create history dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".history-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			tmp.Close()
+			return fmt.Errorf("write history: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write history: %w", err)
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("set permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Filter selects entries by command, time range and text.
+type Filter struct {
+	Command string    // matches the full command or any prefix of it ("copy")
+	Since   time.Time // inclusive; zero means unbounded
+	Until   time.Time // exclusive; zero means unbounded
+	Text    string    // case-insensitive match on request body and results
+}
+
+// Match reports whether e satisfies every set criterion.
+func (f Filter) Match(e Entry) bool {
+	if f.Command != "" && e.Command != f.Command && !strings.HasPrefix(e.Command, f.Command+" ") {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	if f.Text != "" {
+		needle := strings.ToLower(f.Text)
+		var hay []string
+		collectStrings(e.Body, &hay)
+		hay = append(hay, e.Texts()...)
+		found := false
+		for _, h := range hay {
+			if strings.Contains(strings.ToLower(h), needle) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the entries matching f, preserving order.
+func (f Filter) Apply(entries []Entry) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if f.Match(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// ParseTime accepts a date (2006-01-02), an RFC 3339 timestamp, or a
+// relative age such as "36h" or "7d" measured back from now.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if d, err := ParseAge(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use YYYY-MM-DD, RFC 3339, or an age like 7d)", s)
+}
+
+// ParseAge parses a Go duration, additionally accepting a "d" (day) suffix.
+func ParseAge(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		var days int
+		if _, err := fmt.Sscanf(s, "%dd", &days); err == nil && days >= 0 {
+			return time.Duration(days) * 24 * time.Hour, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,373 @@
+// Package mock implements a fake Writesonic API for local development and
+// end-to-end tests. Responses are deterministic: the same request always
+// gets the same text, shaped like the real API's.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+// Options configure the mock server.
+type Options struct {
+	// Latency is added to every response; Jitter adds up to that much more.
+	Latency time.Duration
+	Jitter  time.Duration
+	// Failures are checked in order; the first that fires answers the request.
+	Failures []*Failure
+	// Hang bounds how long a "timeout" failure holds the connection open.
+	Hang time.Duration
+	// Seed makes percentage failures and jitter reproducible.
+	Seed int64
+	// Log receives one line per request, if set.
+	Log io.Writer
+}
+
+// Failure injects an error response. Spec syntax, as parsed by ParseFailure:
+//
+//	[PATH=]KIND[:COUNT|:PERCENT%]
+//
+// KIND is an HTTP status (e.g. 422, 429, 500) or "timeout". COUNT fails the
+// first COUNT matching requests and then lets them through; PERCENT fails
+// that share of requests at random; neither fails every request.
+type Failure struct {
+	Path    string // empty matches every endpoint
+	Status  int
+	Timeout bool
+	Count   int
+	Percent float64
+
+	mu   sync.Mutex
+	seen int
+}
+
+// ParseFailure parses a --fail spec such as "429:2", "500:30%" or
+// "/blog-ideas=timeout".
+func ParseFailure(spec string) (*Failure, error) {
+	f := &Failure{}
+	rest := spec
+	if i := strings.Index(rest, "="); i >= 0 {
+		f.Path = rest[:i]
+		if !strings.HasPrefix(f.Path, "/") {
+			f.Path = "/" + f.Path
+		}
+		rest = rest[i+1:]
+	}
+	kind, when, _ := strings.Cut(rest, ":")
+	if kind == "timeout" {
+		f.Timeout = true
+	} else {
+		n, err := strconv.Atoi(kind)
+		if err != nil || n < 400 || n > 599 {
+			return nil, fmt.Errorf("invalid failure %q: kind must be an HTTP status 400-599 or \"timeout\"", spec)
+		}
+		f.Status = n
+	}
+	switch {
+	case when == "":
+	case strings.HasSuffix(when, "%"):
+		p, err := strconv.ParseFloat(strings.TrimSuffix(when, "%"), 64)
+		if err != nil || p <= 0 || p > 100 {
+			return nil, fmt.Errorf("invalid failure %q: percentage must be between 0 and 100", spec)
+		}
+		f.Percent = p
+	default:
+		n, err := strconv.Atoi(when)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid failure %q: count must be a positive number", spec)
+		}
+		f.Count = n
+	}
+	return f, nil
+}
+
+// String returns the spec f was parsed from, in canonical form.
+func (f *Failure) String() string {
+	s := strconv.Itoa(f.Status)
+	if f.Timeout {
+		s = "timeout"
+	}
+	if f.Path != "" {
+		s = f.Path + "=" + s
+	}
+	switch {
+	case f.Count > 0:
+		s += ":" + strconv.Itoa(f.Count)
+	case f.Percent > 0:
+		s += ":" + strconv.FormatFloat(f.Percent, 'f', -1, 64) + "%"
+	}
+	return s
+}
+
+// fires reports whether f applies to a request for path.
+func (f *Failure) fires(path string, rnd func() float64) bool {
+	if f.Path != "" && f.Path != path {
+		return false
+	}
+	switch {
+	case f.Count > 0:
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.seen++
+		return f.seen <= f.Count
+	case f.Percent > 0:
+		return rnd()*100 < f.Percent
+	}
+	return true
+}
+
+type server struct {
+	opts Options
+
+	mu       sync.Mutex
+	rnd      *rand.Rand
+	requests int // served so far, numbering X-Request-Id
+}
+
+// NewHandler returns an http.Handler serving every endpoint the CLI uses.
+func NewHandler(opts Options) http.Handler {
+	if opts.Hang <= 0 {
+		opts.Hang = 10 * time.Minute
+	}
+	return &server{opts: opts, rnd: rand.New(rand.NewSource(opts.Seed))}
+}
+
+func (s *server) random() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rnd.Float64()
+}
+
+// requestID numbers requests from the seed, so a re-recorded session gets
+// the same IDs as the last one.
+func (s *server) requestID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	return fmt.Sprintf("mock-%d-%06d", s.opts.Seed, s.requests)
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	w.Header().Set("X-Request-Id", s.requestID())
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.serve(rec, r)
+	if s.opts.Log != nil {
+		fmt.Fprintf(s.opts.Log, "%s %s %d %s\n", r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+func (s *server) serve(w http.ResponseWriter, r *http.Request) {
+	if d := s.opts.Latency + time.Duration(s.random()*float64(s.opts.Jitter)); d > 0 {
+		select {
+		case <-time.After(d):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	_, known := writesonic.Endpoints[r.URL.Path]
+	switch {
+	case !known:
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	case r.Method != http.MethodPost:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	case r.Header.Get("X-API-Key") == "":
+		writeError(w, http.StatusUnauthorized, "Missing API key")
+		return
+	}
+
+	// Read the body up front: once it is consumed the server notices a client
+	// that gives up, which ends an injected timeout.
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+
+	for _, f := range s.opts.Failures {
+		if !f.fires(r.URL.Path, s.random) {
+			continue
+		}
+		if f.Timeout {
+			select {
+			case <-time.After(s.opts.Hang):
+			case <-r.Context().Done():
+			}
+			writeError(w, http.StatusGatewayTimeout, "Gateway Timeout")
+			return
+		}
+		if f.Status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		if f.Status == http.StatusUnprocessableEntity {
+			writeValidation(w, "body", "injected", "injected validation failure")
+			return
+		}
+		writeError(w, f.Status, http.StatusText(f.Status))
+		return
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		writeValidation(w, "body", "", "request body must be a JSON object")
+		return
+	}
+	q := r.URL.Query()
+	if err := writesonic.ValidateRequest(r.URL.Path, q, body); err != nil {
+		re := err.(*writesonic.RequestError)
+		if re.Param != "" {
+			writeValidation(w, "query", re.Param, re.Param+" "+re.Message)
+		} else {
+			writeValidation(w, "body", re.Field, re.Field+" "+re.Message)
+		}
+		return
+	}
+
+	copies := 1
+	if n, err := strconv.Atoi(q.Get("num_copies")); err == nil && n > 0 {
+		copies = n
+	}
+	if r.URL.Path == "/landing-pages" {
+		writeJSON(w, http.StatusOK, landingPages(body, copies))
+		return
+	}
+	writeJSON(w, http.StatusOK, textResults(r.URL.Path, q, body, copies))
+}
+
+// textResults builds copies deterministic results for a text endpoint.
+func textResults(path string, q url.Values, body map[string]interface{}, copies int) []writesonic.Result {
+	out := make([]writesonic.Result, copies)
+	for i := range out {
+		out[i] = writesonic.Result{Text: generate(path, q, body, i)}
+	}
+	return out
+}
+
+func generate(path string, q url.Values, body map[string]interface{}, i int) string {
+	str := func(k string) string { s, _ := body[k].(string); return s }
+	tag := fmt.Sprintf("[%s/%s #%d %s]", q.Get("engine"), q.Get("language"), i+1, fingerprint(path, body, i))
+	switch path {
+	case "/blog-ideas":
+		return fmt.Sprintf("%s 7 ways %s will change how you work", tag, str("topic"))
+	case "/ai-article-writer-v3":
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %s\n\n%s %s\n", str("article_title"), tag, str("article_intro"))
+		sections, _ := body["article_sections"].([]interface{})
+		for n, s := range sections {
+			fmt.Fprintf(&b, "\n## %v\n\nSection %d of the article on %s.\n", s, n+1, str("article_title"))
+		}
+		return b.String()
+	case "/instant-article-writer":
+		return fmt.Sprintf("# %s\n\n%s An instant article about %s.\n\n## Conclusion\n\nThat's %s in a nutshell.",
+			str("article_title"), tag, str("article_title"), str("article_title"))
+	case "/landing-page-headlines":
+		return fmt.Sprintf("%s %s: %s, simplified", tag, str("product_name"), str("product_description"))
+	case "/pas":
+		return fmt.Sprintf("%s Problem: ... Agitate: ... Solution: %s — %s", tag, str("product_name"), str("product_description"))
+	case "/aida":
+		return fmt.Sprintf("%s Attention! Interest: %s. Desire: ... Action: try %s today.", tag, str("product_description"), str("product_name"))
+	case "/call-to-action":
+		return fmt.Sprintf("%s Start with %s now", tag, str("product_name"))
+	case "/bulletpoint-answers":
+		return fmt.Sprintf("%s\n- First point on %s\n- Second point\n- Third point", tag, str("question"))
+	case "/content-rephrase":
+		return fmt.Sprintf("%s %s", tag, str("content_to_rephrase"))
+	case "/content-shorten":
+		return fmt.Sprintf("%s %s", tag, shorten(str("content_to_shorten")))
+	case "/tone-changer":
+		return fmt.Sprintf("%s (%s) %s", tag, str("tone"), str("content_to_change"))
+	case "/rewrite-with-keywords":
+		return fmt.Sprintf("%s %s [keywords: %s]", tag, str("content"), str("keywords"))
+	case "/paragraph-writer":
+		return fmt.Sprintf("%s A paragraph about %s.", tag, str("topic"))
+	case "/meta-blog":
+		return fmt.Sprintf("%s Meta title: %s | Meta description: %s", tag, str("blog_title"), str("blog_description"))
+	case "/conclusion-writer":
+		return fmt.Sprintf("%s In conclusion, %s matters.", tag, str("topic"))
+	}
+	return tag
+}
+
+func landingPages(body map[string]interface{}, copies int) []writesonic.LandingPage {
+	str := func(k string) string { s, _ := body[k].(string); return s }
+	out := make([]writesonic.LandingPage, copies)
+	for i := range out {
+		name := str("product_name")
+		out[i] = writesonic.LandingPage{
+			Title:               fmt.Sprintf("%s #%d %s", name, i+1, fingerprint("/landing-pages", body, i)),
+			Subtitle:            str("product_description"),
+			MainFeatureTitle:    "Why " + name,
+			MainFeatureSubtitle: "Everything you need in one place",
+			Feature1Title:       str("feature_1"),
+			Feature1Subtitle:    "Built for " + strings.ToLower(str("feature_1")),
+			Feature2Title:       str("feature_2"),
+			Feature2Subtitle:    "Built for " + strings.ToLower(str("feature_2")),
+			Feature3Title:       str("feature_3"),
+			Feature3Subtitle:    "Built for " + strings.ToLower(str("feature_3")),
+			CTA:                 "Get started with " + name,
+			Button:              "Try it free",
+		}
+	}
+	return out
+}
+
+// fingerprint is a short hash tying a result to its request, so different
+// inputs visibly produce different outputs.
+func fingerprint(path string, body map[string]interface{}, i int) string {
+	data, _ := json.Marshal(body)
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s\n%s\n%d", path, data, i)
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+func shorten(s string) string {
+	words := strings.Fields(s)
+	if len(words) > 6 {
+		words = append(words[:len(words)/2], "…")
+	}
+	return strings.Join(words, " ")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"detail": msg})
+}
+
+// writeValidation answers 422 in the API's FastAPI-style format.
+func writeValidation(w http.ResponseWriter, where, field, msg string) {
+	loc := []string{where}
+	if field != "" {
+		loc = append(loc, field)
+	}
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+		"detail": []map[string]interface{}{{"loc": loc, "msg": msg, "type": "value_error"}},
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,153 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/the20100/writesonic-cli/pkg/writesonic"
+)
+
+// post sends a blog-ideas request to srv with the given query string.
+func post(t *testing.T, srv *httptest.Server, query string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/blog-ideas?"+query, strings.NewReader(`{"topic":"remote work"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-API-Key", "test")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func decodeResults(t *testing.T, resp *http.Response) []writesonic.Result {
+	t.Helper()
+	var results []writesonic.Result
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		t.Fatal(err)
+	}
+	return results
+}
+
+func TestNumCopies(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(Options{}))
+	defer srv.Close()
+
+	first := decodeResults(t, post(t, srv, "engine=good&language=en&num_copies=3"))
+	if len(first) != 3 {
+		t.Fatalf("got %d results, want 3", len(first))
+	}
+	if first[0].Text == first[1].Text {
+		t.Errorf("copies are identical: %q", first[0].Text)
+	}
+	again := decodeResults(t, post(t, srv, "engine=good&language=en&num_copies=3"))
+	for i := range first {
+		if first[i].Text != again[i].Text {
+			t.Errorf("copy %d changed between identical requests: %q, %q", i+1, first[i].Text, again[i].Text)
+		}
+	}
+	if n := len(decodeResults(t, post(t, srv, "engine=good&language=en"))); n != 1 {
+		t.Errorf("without num_copies got %d results, want 1", n)
+	}
+}
+
+func TestRequestIDsAreDeterministic(t *testing.T) {
+	ids := func() []string {
+		srv := httptest.NewServer(NewHandler(Options{Seed: 7}))
+		defer srv.Close()
+		var out []string
+		for i := 0; i < 3; i++ {
+			out = append(out, post(t, srv, "engine=good&language=en").Header.Get("X-Request-Id"))
+		}
+		return out
+	}
+	a, b := ids(), ids()
+	for i := range a {
+		if a[i] != b[i] {
+			t.Errorf("request %d: IDs %q and %q differ across runs", i+1, a[i], b[i])
+		}
+	}
+	if a[0] == a[1] {
+		t.Errorf("requests share the ID %q", a[0])
+	}
+}
+
+func TestFailCount(t *testing.T) {
+	f, err := ParseFailure("500:2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewHandler(Options{Failures: []*Failure{f}}))
+	defer srv.Close()
+
+	for i, want := range []int{500, 500, 200, 200} {
+		if got := post(t, srv, "engine=good&language=en").StatusCode; got != want {
+			t.Errorf("request %d: status %d, want %d", i+1, got, want)
+		}
+	}
+}
+
+func TestRetryAfterOn429(t *testing.T) {
+	f, err := ParseFailure("/blog-ideas=429")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewHandler(Options{Failures: []*Failure{f}}))
+	defer srv.Close()
+
+	resp := post(t, srv, "engine=good&language=en")
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status %d, want 429", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want 1", got)
+	}
+}
+
+// TestClientRetriesMockFailures drives the SDK's retry handling end to end:
+// a transient 503 is retried, a 500 is not.
+func TestClientRetriesMockFailures(t *testing.T) {
+	transient, err := ParseFailure("/blog-ideas=503:2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	permanent, err := ParseFailure("/pas=500")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewHandler(Options{Failures: []*Failure{transient, permanent}}))
+	defer srv.Close()
+
+	retries := 0
+	c := writesonic.NewClient("test",
+		writesonic.WithBaseURL(srv.URL),
+		writesonic.WithRetryPolicy(writesonic.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		writesonic.WithRetryNotify(func(int, time.Duration, error) { retries++ }),
+	)
+	results, err := c.BlogIdeas(context.Background(), writesonic.BlogIdeasRequest{Topic: "remote work"}, writesonic.WithCopies(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retries != 2 || len(results) != 2 {
+		t.Errorf("retries = %d, results = %d; want 2 and 2", retries, len(results))
+	}
+
+	retries = 0
+	_, err = c.PAS(context.Background(), writesonic.ProductRequest{ProductName: "CloudStore", ProductDescription: "Cloud storage"})
+	var apiErr *writesonic.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("err = %v, want a 500 APIError", err)
+	}
+	if retries != 0 {
+		t.Errorf("500 was retried %d times", retries)
+	}
+}
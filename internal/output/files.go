@@ -0,0 +1,107 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExistsPolicy says what WriteFile does when the target already exists.
+type ExistsPolicy string
+
+const (
+	Overwrite ExistsPolicy = "overwrite" // replace the file
+	Skip      ExistsPolicy = "skip"      // keep the file, write nothing
+	Suffix    ExistsPolicy = "suffix"    // write name-2.ext, name-3.ext, ...
+)
+
+// ExistsPolicies lists the accepted policy names.
+var ExistsPolicies = []string{string(Overwrite), string(Skip), string(Suffix)}
+
+// ParseExistsPolicy validates a policy name.
+func ParseExistsPolicy(s string) (ExistsPolicy, error) {
+	for _, p := range ExistsPolicies {
+		if s == p {
+			return ExistsPolicy(s), nil
+		}
+	}
+	return "", fmt.Errorf("invalid policy %q (use %s)", s, strings.Join(ExistsPolicies, ", "))
+}
+
+// WriteFile atomically writes data to path, creating parent directories,
+// and returns the path actually written, or "" when skipped under Skip.
+func WriteFile(path string, data []byte, policy ExistsPolicy) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	switch policy {
+	case Skip:
+		if exists(path) {
+			return "", nil
+		}
+	case Suffix:
+		ext := filepath.Ext(path)
+		base := strings.TrimSuffix(path, ext)
+		for n := 2; exists(path); n++ {
+			path = fmt.Sprintf("%s-%d%s", base, n, ext)
+		}
+	}
+	return path, WriteFileAtomic(path, data, 0o644)
+}
+
+// WriteFileAtomic replaces path with data via a temp file and rename, so
+// readers never see a partial file.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// Slugify turns s into a lowercase, hyphen-separated name safe for file
+// names and URLs, at most 60 characters long.
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	slug := b.String()
+	if len(slug) > 60 {
+		slug = slug[:60]
+		for !utf8.ValidString(slug) {
+			slug = slug[:len(slug)-1]
+		}
+		slug = strings.TrimRight(slug, "-")
+	}
+	return slug
+}
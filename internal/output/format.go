@@ -0,0 +1,188 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Role says what part of a generated result a field is, so markup formats
+// can choose an element for it.
+type Role int
+
+const (
+	RoleBody       Role = iota // free text, possibly Markdown
+	RoleTitle                  // page title (h1)
+	RoleHeading                // section heading (h2)
+	RoleSubheading             // sub-section heading (h3)
+	RoleParagraph              // plain paragraph
+	RoleCTA                    // call to action
+	RoleButton                 // button label
+)
+
+// Field is one named value of a result.
+type Field struct {
+	Key   string // machine name, used by yaml and csv
+	Label string // human name, used by text
+	Value string
+	Role  Role
+}
+
+// Record is one generated result as ordered fields.
+type Record []Field
+
+// Document is what a Format renders: the results as values for structured
+// encoders and as records for everything else.
+type Document struct {
+	Items   []interface{}
+	Records []Record
+	Pretty  bool // indent json
+}
+
+// Format renders documents in one output format.
+type Format struct {
+	Name   string
+	Ext    string // file extension without the dot
+	Render func(w io.Writer, d Document) error
+}
+
+var formats = map[string]Format{}
+
+// RegisterFormat adds f to the formats accepted by --format, replacing any
+// format of the same name.
+func RegisterFormat(f Format) {
+	formats[f.Name] = f
+}
+
+// LookupFormat returns the format registered under name.
+func LookupFormat(name string) (Format, error) {
+	f, ok := formats[name]
+	if !ok {
+		return Format{}, fmt.Errorf("unknown format %q (use %s)", name, strings.Join(FormatNames(), ", "))
+	}
+	return f, nil
+}
+
+// FormatNames lists the registered format names, sorted.
+func FormatNames() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterFormat(Format{Name: "text", Ext: "txt", Render: renderText})
+	RegisterFormat(Format{Name: "json", Ext: "json", Render: renderJSON})
+	RegisterFormat(Format{Name: "ndjson", Ext: "ndjson", Render: renderNDJSON})
+	RegisterFormat(Format{Name: "yaml", Ext: "yaml", Render: renderYAML})
+	RegisterFormat(Format{Name: "csv", Ext: "csv", Render: renderCSV})
+	RegisterFormat(Format{Name: "markdown", Ext: "md", Render: renderMarkdown})
+	RegisterFormat(Format{Name: "html", Ext: "html", Render: renderHTML})
+}
+
+// renderText prints body-only records as numbered text blocks and other
+// records as key/value blocks, as the CLI always has.
+func renderText(w io.Writer, d Document) error {
+	for i, rec := range d.Records {
+		if len(d.Records) > 1 {
+			fmt.Fprintf(w, "--- Result %d ---\n", i+1)
+		}
+		if body, ok := bodyOnly(rec); ok {
+			fmt.Fprintln(w, body)
+		} else {
+			if len(d.Records) > 1 {
+				fmt.Fprintln(w)
+			}
+			rows := make([][]string, len(rec))
+			for j, f := range rec {
+				rows[j] = []string{f.Label, f.Value}
+			}
+			FprintKeyValue(w, rows)
+		}
+		if i < len(d.Records)-1 {
+			fmt.Fprintln(w)
+		}
+	}
+	return nil
+}
+
+func renderJSON(w io.Writer, d Document) error {
+	enc := json.NewEncoder(w)
+	if d.Pretty {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(d.Items)
+}
+
+// renderNDJSON writes one compact JSON value per line.
+func renderNDJSON(w io.Writer, d Document) error {
+	enc := json.NewEncoder(w)
+	for _, item := range d.Items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderYAML writes the records as a sequence of mappings, keeping field
+// order.
+func renderYAML(w io.Writer, d Document) error {
+	seq := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, rec := range d.Records {
+		m := &yaml.Node{Kind: yaml.MappingNode}
+		for _, f := range rec {
+			m.Content = append(m.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: f.Key},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: f.Value})
+		}
+		seq.Content = append(seq.Content, m)
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(seq); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// renderCSV writes a header row of field keys, then one row per record.
+func renderCSV(w io.Writer, d Document) error {
+	cw := csv.NewWriter(w)
+	for i, rec := range d.Records {
+		if i == 0 {
+			header := make([]string, len(rec))
+			for j, f := range rec {
+				header[j] = f.Key
+			}
+			if err := cw.Write(header); err != nil {
+				return err
+			}
+		}
+		row := make([]string, len(rec))
+		for j, f := range rec {
+			row[j] = f.Value
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// bodyOnly returns the text of a record that is a single body field.
+func bodyOnly(rec Record) (string, bool) {
+	if len(rec) == 1 && rec[0].Role == RoleBody {
+		return rec[0].Value, true
+	}
+	return "", false
+}
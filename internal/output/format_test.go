@@ -0,0 +1,162 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// landingPage is a landing page as the landing commands pass it in.
+var landingPage = Document{Records: []Record{{
+	{Key: "title", Label: "Title", Value: "CloudStore", Role: RoleTitle},
+	{Key: "subtitle", Label: "Subtitle", Value: "Store, sync & share", Role: RoleParagraph},
+	{Key: "main_feature_title", Label: "Main Feature Title", Value: "Why us", Role: RoleHeading},
+	{Key: "main_feature_subtitle", Label: "Main Feature Subtitle", Value: "Fast <always>", Role: RoleParagraph},
+	{Key: "feature_1_title", Label: "Feature 1 Title", Value: "Secure", Role: RoleSubheading},
+	{Key: "feature_1_subtitle", Label: "Feature 1 Subtitle", Value: "Encrypted\nat rest", Role: RoleParagraph},
+	{Key: "cta", Label: "CTA", Value: "Start today", Role: RoleCTA},
+	{Key: "button", Label: "Button", Value: "Sign up", Role: RoleButton},
+}}}
+
+// twoCopies is a text result with two copies.
+var twoCopies = Document{Records: []Record{
+	{{Key: "text", Label: "Text", Value: "## Tips\n\n- Plan ahead\n- Write \"daily\"\n\nDone.", Role: RoleBody}},
+	{{Key: "text", Label: "Text", Value: "1. One\n2. Two", Role: RoleBody}},
+}}
+
+func TestFormatRegistry(t *testing.T) {
+	want := []string{"csv", "html", "json", "markdown", "ndjson", "text", "yaml"}
+	if got := FormatNames(); !slices.Equal(got, want) {
+		t.Errorf("FormatNames() = %v, want %v", got, want)
+	}
+	for name, ext := range map[string]string{"text": "txt", "markdown": "md", "html": "html", "csv": "csv"} {
+		f, err := LookupFormat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Name != name || f.Ext != ext {
+			t.Errorf("LookupFormat(%q) = %s/.%s, want %s/.%s", name, f.Name, f.Ext, name, ext)
+		}
+	}
+	if _, err := LookupFormat("md"); err == nil || !strings.Contains(err.Error(), "csv, html, json") {
+		t.Errorf("LookupFormat(md) error = %v, want the list of formats", err)
+	}
+
+	RegisterFormat(Format{Name: "plain", Ext: "txt", Render: func(w io.Writer, d Document) error {
+		_, err := io.WriteString(w, "plain\n")
+		return err
+	}})
+	t.Cleanup(func() { delete(formats, "plain") })
+	f, err := LookupFormat("plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := f.Render(&buf, twoCopies); err != nil || buf.String() != "plain\n" {
+		t.Errorf("registered format rendered %q, %v", buf.String(), err)
+	}
+	if !slices.Contains(FormatNames(), "plain") {
+		t.Errorf("FormatNames() = %v, missing plain", FormatNames())
+	}
+}
+
+func TestRender(t *testing.T) {
+	for _, tc := range []struct {
+		format string
+		doc    Document
+		want   string
+	}{
+		{"markdown", landingPage, `# CloudStore
+
+Store, sync & share
+
+## Why us
+
+Fast <always>
+
+### Secure
+
+Encrypted
+at rest
+
+**Start today**
+
+[Sign up](#)
+`},
+		{"markdown", twoCopies, `## Tips
+
+- Plan ahead
+- Write "daily"
+
+Done.
+
+---
+
+1. One
+2. Two
+`},
+		{"html", landingPage, `<article>
+  <header>
+    <h1>CloudStore</h1>
+    <p>Store, sync &amp; share</p>
+  </header>
+  <section>
+    <h2>Why us</h2>
+    <p>Fast &lt;always&gt;</p>
+  </section>
+  <section>
+    <h3>Secure</h3>
+    <p>Encrypted at rest</p>
+  </section>
+  <footer>
+    <p><strong>Start today</strong></p>
+    <button type="button">Sign up</button>
+  </footer>
+</article>
+`},
+		{"html", twoCopies, `<article>
+  <h2>Tips</h2>
+  <ul>
+    <li>Plan ahead</li>
+    <li>Write &#34;daily&#34;</li>
+  </ul>
+  <p>Done.</p>
+</article>
+
+<article>
+  <ol>
+    <li>One</li>
+    <li>Two</li>
+  </ol>
+</article>
+`},
+		{"csv", landingPage, `title,subtitle,main_feature_title,main_feature_subtitle,feature_1_title,feature_1_subtitle,cta,button
+CloudStore,"Store, sync & share",Why us,Fast <always>,Secure,"Encrypted
+at rest",Start today,Sign up
+`},
+		{"csv", twoCopies, `text
+"## Tips
+
+- Plan ahead
+- Write ""daily""
+
+Done."
+"1. One
+2. Two"
+`},
+	} {
+		f, err := LookupFormat(tc.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := f.Render(&buf, tc.doc); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tc.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tc.format, buf.String(), tc.want)
+		}
+	}
+}
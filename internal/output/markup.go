@@ -0,0 +1,161 @@
+package output
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// renderMarkdown writes each record as Markdown sections, separated by
+// thematic breaks.
+func renderMarkdown(w io.Writer, d Document) error {
+	for i, rec := range d.Records {
+		if i > 0 {
+			fmt.Fprint(w, "\n---\n\n")
+		}
+		var blocks []string
+		for _, f := range rec {
+			v := strings.TrimSpace(f.Value)
+			if v == "" {
+				continue
+			}
+			switch f.Role {
+			case RoleTitle:
+				v = "# " + oneLine(v)
+			case RoleHeading:
+				v = "## " + oneLine(v)
+			case RoleSubheading:
+				v = "### " + oneLine(v)
+			case RoleCTA:
+				v = "**" + oneLine(v) + "**"
+			case RoleButton:
+				v = "[" + oneLine(v) + "](#)"
+			}
+			blocks = append(blocks, v)
+		}
+		fmt.Fprintln(w, strings.Join(blocks, "\n\n"))
+	}
+	return nil
+}
+
+// renderHTML writes each record as an <article> fragment: a title and the
+// paragraph after it form a <header>, each heading opens a <section>, and a
+// call to action and button form a <footer>. Body text is converted from
+// Markdown headings, lists and paragraphs.
+func renderHTML(w io.Writer, d Document) error {
+	for i, rec := range d.Records {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, "<article>")
+		open := ""
+		enter := func(tag string) {
+			if open == tag && tag == "footer" {
+				return
+			}
+			if open != "" {
+				fmt.Fprintf(w, "  </%s>\n", open)
+			}
+			open = tag
+			if tag != "" {
+				fmt.Fprintf(w, "  <%s>\n", tag)
+			}
+		}
+		for _, f := range rec {
+			v := strings.TrimSpace(f.Value)
+			if v == "" {
+				continue
+			}
+			esc := html.EscapeString(oneLine(v))
+			switch f.Role {
+			case RoleTitle:
+				enter("header")
+				fmt.Fprintf(w, "    <h1>%s</h1>\n", esc)
+			case RoleHeading:
+				enter("section")
+				fmt.Fprintf(w, "    <h2>%s</h2>\n", esc)
+			case RoleSubheading:
+				enter("section")
+				fmt.Fprintf(w, "    <h3>%s</h3>\n", esc)
+			case RoleParagraph:
+				if open == "" {
+					fmt.Fprintf(w, "  <p>%s</p>\n", esc)
+				} else {
+					fmt.Fprintf(w, "    <p>%s</p>\n", esc)
+				}
+			case RoleCTA:
+				enter("footer")
+				fmt.Fprintf(w, "    <p><strong>%s</strong></p>\n", esc)
+			case RoleButton:
+				enter("footer")
+				fmt.Fprintf(w, "    <button type=\"button\">%s</button>\n", esc)
+			default:
+				enter("")
+				writeMarkdownHTML(w, v, "  ")
+			}
+		}
+		enter("")
+		fmt.Fprintln(w, "</article>")
+	}
+	return nil
+}
+
+var (
+	headingLine = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	bulletLine  = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedLine = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+)
+
+// writeMarkdownHTML converts the block structure of generated Markdown
+// (headings, bullet and numbered lists, paragraphs) to HTML. Inline markup
+// is escaped, not interpreted.
+func writeMarkdownHTML(w io.Writer, text, indent string) {
+	var para []string
+	list := ""
+	flush := func() {
+		if len(para) > 0 {
+			fmt.Fprintf(w, "%s<p>%s</p>\n", indent, html.EscapeString(strings.Join(para, " ")))
+			para = nil
+		}
+		if list != "" {
+			fmt.Fprintf(w, "%s</%s>\n", indent, list)
+			list = ""
+		}
+	}
+	item := func(tag, s string) {
+		if list != tag {
+			flush()
+			fmt.Fprintf(w, "%s<%s>\n", indent, tag)
+			list = tag
+		}
+		fmt.Fprintf(w, "%s  <li>%s</li>\n", indent, html.EscapeString(s))
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case headingLine.MatchString(line):
+			flush()
+			m := headingLine.FindStringSubmatch(line)
+			fmt.Fprintf(w, "%s<h%d>%s</h%[2]d>\n", indent, len(m[1]), html.EscapeString(m[2]))
+		case bulletLine.MatchString(line):
+			item("ul", bulletLine.FindStringSubmatch(line)[1])
+		case orderedLine.MatchString(line):
+			item("ol", orderedLine.FindStringSubmatch(line)[1])
+		default:
+			if list != "" {
+				flush()
+			}
+			para = append(para, strings.TrimSpace(line))
+		}
+	}
+	flush()
+}
+
+// oneLine collapses whitespace, including newlines, to single spaces.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -3,6 +3,7 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"text/tabwriter"
@@ -39,7 +40,12 @@ func PrintTable(headers []string, rows [][]string) {
 
 // PrintKeyValue prints two-column key/value rows.
 func PrintKeyValue(rows [][]string) {
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	FprintKeyValue(os.Stdout, rows)
+}
+
+// FprintKeyValue is PrintKeyValue writing to out.
+func FprintKeyValue(out io.Writer, rows [][]string) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
 	for _, row := range rows {
 		if len(row) < 2 || row[1] == "" || row[1] == "-" {
 			continue
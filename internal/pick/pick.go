@@ -0,0 +1,406 @@
+// Package pick is a full-screen terminal picker for generated variations:
+// they are shown side by side, can be scrolled, starred, edited in place and
+// regenerated one at a time, and the chosen ones are returned to the caller.
+package pick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ErrCancelled is returned when the picker is closed without choosing.
+var ErrCancelled = errors.New("pick cancelled")
+
+// Options configure a picker.
+type Options struct {
+	Title string
+
+	// Regenerate returns a new text for variant i (0-based); nil disables
+	// regenerating.
+	Regenerate func(ctx context.Context, i int) (string, error)
+
+	// Output is where the picker draws, normally the terminal on stderr so
+	// stdout stays free for the chosen results. Input is read from the TTY.
+	Output io.Writer
+}
+
+// Choice is a chosen variant.
+type Choice struct {
+	Index   int    // 0-based position among the variants
+	Text    string // its text, after any edit or regeneration
+	Changed bool   // edited or regenerated
+}
+
+// Run shows texts and returns the starred variants in order, or the focused
+// one if none is starred when the user confirms.
+func Run(ctx context.Context, texts []string, opts Options) ([]Choice, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	m := newModel(ctx, texts, opts)
+	final, err := tea.NewProgram(m,
+		tea.WithContext(ctx),
+		tea.WithAltScreen(),
+		tea.WithInputTTY(),
+		tea.WithOutput(opts.Output),
+	).Run()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return final.(*model).choices()
+}
+
+// minColumnWidth is the narrowest column before fewer columns are shown and
+// the rest are reached by moving left and right.
+const minColumnWidth = 32
+
+type variant struct {
+	text    string
+	starred bool
+	changed bool
+	busy    bool // being regenerated
+	offset  int  // first body line shown
+}
+
+type regenerated struct {
+	index int
+	text  string
+	err   error
+}
+
+type model struct {
+	ctx      context.Context
+	opts     Options
+	variants []variant
+
+	focus  int
+	first  int // first visible column
+	width  int
+	height int
+
+	editing bool
+	editor  textarea.Model
+	status  string
+	styles  styles
+
+	done      bool
+	cancelled bool
+}
+
+func newModel(ctx context.Context, texts []string, opts Options) *model {
+	m := &model{ctx: ctx, opts: opts, width: 80, height: 24, styles: newStyles(opts.Output)}
+	for _, t := range texts {
+		m.variants = append(m.variants, variant{text: t})
+	}
+	m.editor = textarea.New()
+	m.editor.ShowLineNumbers = false
+	m.editor.Prompt = ""
+	m.editor.CharLimit = 0
+	// The default styles use adaptive colors, which query the terminal
+	// while the picker owns it.
+	plain := m.styles.plain
+	m.editor.FocusedStyle = textarea.Style{Base: plain, CursorLine: plain, CursorLineNumber: plain,
+		EndOfBuffer: plain, LineNumber: plain, Placeholder: plain, Prompt: plain, Text: plain}
+	m.editor.BlurredStyle = m.editor.FocusedStyle
+	m.editor.Cursor.Style, m.editor.Cursor.TextStyle = plain, plain
+	return m
+}
+
+func (m *model) Init() tea.Cmd { return nil }
+
+func (m *model) choices() ([]Choice, error) {
+	if m.cancelled || !m.done {
+		return nil, ErrCancelled
+	}
+	var out []Choice
+	for i, v := range m.variants {
+		if v.starred {
+			out = append(out, Choice{Index: i, Text: v.text, Changed: v.changed})
+		}
+	}
+	if len(out) == 0 {
+		v := m.variants[m.focus]
+		out = append(out, Choice{Index: m.focus, Text: v.text, Changed: v.changed})
+	}
+	return out, nil
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.sizeEditor()
+		return m, nil
+	case regenerated:
+		v := &m.variants[msg.index]
+		v.busy = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("#%d: %v", msg.index+1, msg.err)
+			return m, nil
+		}
+		v.text, v.changed, v.offset = msg.text, true, 0
+		m.status = fmt.Sprintf("#%d regenerated", msg.index+1)
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateEditor(msg)
+		}
+		return m.updateKey(msg)
+	}
+	return m, nil
+}
+
+func (m *model) updateEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		m.editor.Blur()
+		m.status = "edit discarded"
+		return m, nil
+	case "ctrl+s":
+		v := &m.variants[m.focus]
+		if text := strings.TrimSpace(m.editor.Value()); text != v.text {
+			v.text, v.changed = text, true
+			m.status = fmt.Sprintf("#%d edited", m.focus+1)
+		}
+		m.editing = false
+		m.editor.Blur()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+func (m *model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+	v := &m.variants[m.focus]
+	switch key := msg.String(); key {
+	case "q", "esc", "ctrl+c":
+		m.cancelled = true
+		return m, tea.Quit
+	case "enter":
+		m.done = true
+		return m, tea.Quit
+	case "left", "h", "shift+tab":
+		m.move(-1)
+	case "right", "l", "tab":
+		m.move(1)
+	case "up", "k":
+		m.scroll(-1)
+	case "down", "j":
+		m.scroll(1)
+	case "pgup":
+		m.scroll(-m.bodyHeight())
+	case "pgdown":
+		m.scroll(m.bodyHeight())
+	case "home", "g":
+		v.offset = 0
+	case " ", "s":
+		v.starred = !v.starred
+	case "a":
+		all := true
+		for _, v := range m.variants {
+			all = all && v.starred
+		}
+		for i := range m.variants {
+			m.variants[i].starred = !all
+		}
+	case "e":
+		if v.busy {
+			break
+		}
+		m.editing = true
+		m.editor.SetValue(v.text)
+		m.sizeEditor()
+		return m, m.editor.Focus()
+	case "r":
+		if m.opts.Regenerate == nil {
+			m.status = "regenerating isn't available here"
+			break
+		}
+		if v.busy {
+			break
+		}
+		v.busy = true
+		i, regenerate, ctx := m.focus, m.opts.Regenerate, m.ctx
+		return m, func() tea.Msg {
+			text, err := regenerate(ctx, i)
+			return regenerated{index: i, text: text, err: err}
+		}
+	default:
+		if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			if n := int(key[0] - '1'); n < len(m.variants) {
+				m.move(n - m.focus)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *model) move(delta int) {
+	m.focus = (m.focus + delta + len(m.variants)) % len(m.variants)
+	cols := m.columns()
+	if m.focus < m.first {
+		m.first = m.focus
+	} else if m.focus >= m.first+cols {
+		m.first = m.focus - cols + 1
+	}
+}
+
+func (m *model) scroll(delta int) {
+	v := &m.variants[m.focus]
+	max := len(m.wrap(v.text)) - m.bodyHeight()
+	v.offset += delta
+	if v.offset > max {
+		v.offset = max
+	}
+	if v.offset < 0 {
+		v.offset = 0
+	}
+}
+
+// columns is how many variants fit side by side.
+func (m *model) columns() int {
+	cols := m.width / minColumnWidth
+	if cols > len(m.variants) {
+		cols = len(m.variants)
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	return cols
+}
+
+// columnWidth is the width inside a column's border and padding.
+func (m *model) columnWidth() int {
+	w := m.width/m.columns() - 4
+	if w < 10 {
+		w = 10
+	}
+	return w
+}
+
+// bodyHeight is the number of text lines a column shows: the screen less the
+// title, footer and status lines, the border and the column header.
+func (m *model) bodyHeight() int {
+	h := m.height - 3 - 2 - 1
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+func (m *model) sizeEditor() {
+	m.editor.SetWidth(m.columnWidth())
+	m.editor.SetHeight(m.bodyHeight())
+}
+
+func (m *model) wrap(text string) []string {
+	wrapped := m.styles.plain.Width(m.columnWidth()).Render(text)
+	return strings.Split(wrapped, "\n")
+}
+
+// styles are bound to the picker's output, so colors follow that terminal
+// even when stdout is piped.
+type styles struct {
+	title, header, star, dim, column, plain lipgloss.Style
+	focused                                 lipgloss.Color
+}
+
+func newStyles(w io.Writer) styles {
+	r := lipgloss.NewRenderer(w)
+	return styles{
+		title:   r.NewStyle().Bold(true),
+		header:  r.NewStyle().Bold(true),
+		star:    r.NewStyle().Foreground(lipgloss.Color("220")),
+		dim:     r.NewStyle().Faint(true),
+		column:  r.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1),
+		plain:   r.NewStyle(),
+		focused: lipgloss.Color("212"),
+	}
+}
+
+func (m *model) View() string {
+	if m.done || m.cancelled {
+		return ""
+	}
+	starred := 0
+	for _, v := range m.variants {
+		if v.starred {
+			starred++
+		}
+	}
+	title := fmt.Sprintf("%d variations, %d starred", len(m.variants), starred)
+	if m.opts.Title != "" {
+		title = m.opts.Title + " — " + title
+	}
+
+	cols := m.columns()
+	var boxes []string
+	for i := m.first; i < m.first+cols && i < len(m.variants); i++ {
+		boxes = append(boxes, m.column(i))
+	}
+
+	help := "←/→ move  ↑/↓ scroll  space star  a star all  e edit  r regenerate  enter emit  q quit"
+	if m.editing {
+		help = "ctrl+s save  esc discard"
+	}
+	more := ""
+	if cols < len(m.variants) {
+		more = fmt.Sprintf("  (showing %d-%d of %d)", m.first+1, m.first+cols, len(m.variants))
+	}
+	return m.styles.title.Render(title) + m.styles.dim.Render(more) + "\n" +
+		lipgloss.JoinHorizontal(lipgloss.Top, boxes...) + "\n" +
+		m.styles.dim.Render(help) + "\n" + m.status
+}
+
+func (m *model) column(i int) string {
+	v := m.variants[i]
+	header := fmt.Sprintf("#%d", i+1)
+	if v.starred {
+		header += " " + m.styles.star.Render("★")
+	}
+	if v.changed {
+		header += m.styles.dim.Render(" (changed)")
+	}
+	if v.busy {
+		header += m.styles.dim.Render(" regenerating…")
+	}
+
+	height := m.bodyHeight()
+	var body string
+	if m.editing && i == m.focus {
+		body = m.editor.View()
+	} else {
+		lines := m.wrap(v.text)
+		end := v.offset + height
+		if end > len(lines) {
+			end = len(lines)
+		}
+		shown := lines[v.offset:end]
+		for len(shown) < height {
+			shown = append(shown, "")
+		}
+		if end < len(lines) {
+			shown[height-1] = m.styles.dim.Render(fmt.Sprintf("↓ %d more lines", len(lines)-end))
+		}
+		body = strings.Join(shown, "\n")
+	}
+
+	style := m.styles.column.Width(m.columnWidth() + 2)
+	if i == m.focus {
+		style = style.BorderForeground(m.styles.focused)
+	}
+	return style.Render(m.styles.header.Render(header) + "\n" + body)
+}
@@ -0,0 +1,271 @@
+// Package site renders generated articles as content files for static site
+// generators: Markdown with front matter, placed where each generator looks
+// for posts.
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Page is an article and its front matter fields.
+type Page struct {
+	Title       string
+	Slug        string
+	Date        time.Time
+	Language    string
+	Tags        []string
+	Description string
+	MetaTitle   string // SEO title, when it differs from Title
+	Body        string // Markdown
+}
+
+// Generator describes one static site generator's conventions.
+type Generator struct {
+	Name       string
+	ContentDir string // where posts live, relative to the site root
+	Ext        string
+	TOML       bool   // TOML (+++) front matter instead of YAML (---)
+	DateKey    string // front matter key for the publication date
+	DateLayout string
+	DatedNames bool // prefix file names with YYYY-MM-DD-
+	MDX        bool // escape characters MDX would read as JSX
+}
+
+// Generators lists the supported generators.
+var Generators = []Generator{
+	{Name: "hugo", ContentDir: "content/posts", Ext: "md", TOML: true, DateKey: "date", DateLayout: time.RFC3339},
+	{Name: "jekyll", ContentDir: "_posts", Ext: "md", DateKey: "date", DateLayout: "2006-01-02 15:04:05 -0700", DatedNames: true},
+	{Name: "astro", ContentDir: "src/content/blog", Ext: "md", DateKey: "pubDate", DateLayout: "2006-01-02"},
+	{Name: "mdx", ContentDir: "src/content/blog", Ext: "mdx", DateKey: "pubDate", DateLayout: "2006-01-02", MDX: true},
+}
+
+// Names lists the generator names.
+func Names() []string {
+	names := make([]string, len(Generators))
+	for i, g := range Generators {
+		names[i] = g.Name
+	}
+	return names
+}
+
+// Lookup returns the generator called name.
+func Lookup(name string) (Generator, error) {
+	for _, g := range Generators {
+		if g.Name == name {
+			return g, nil
+		}
+	}
+	return Generator{}, fmt.Errorf("unknown site generator %q (use %s)", name, strings.Join(Names(), ", "))
+}
+
+// Path returns where p belongs under the site root.
+func (g Generator) Path(root string, p Page) string {
+	name := p.Slug + "." + g.Ext
+	if g.DatedNames {
+		name = p.Date.Format("2006-01-02") + "-" + name
+	}
+	return filepath.Join(root, filepath.FromSlash(g.ContentDir), name)
+}
+
+// Render returns p as a content file: front matter, then the body with a
+// leading "# Title" heading removed, since themes print the title themselves.
+func (g Generator) Render(p Page) ([]byte, error) {
+	var buf bytes.Buffer
+	fields := g.frontMatter(p)
+	if g.TOML {
+		buf.WriteString("+++\n")
+		for _, f := range fields {
+			fmt.Fprintf(&buf, "%s = %s\n", f.key, tomlValue(f.value))
+		}
+		buf.WriteString("+++\n")
+	} else {
+		m := &yaml.Node{Kind: yaml.MappingNode}
+		for _, f := range fields {
+			m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f.key}, yamlValue(f.value))
+		}
+		buf.WriteString("---\n")
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(m); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("---\n")
+	}
+
+	body := stripTitle(p.Body)
+	if g.MDX {
+		body = escapeMDX(body)
+	}
+	buf.WriteString("\n" + strings.TrimSpace(body) + "\n")
+	return buf.Bytes(), nil
+}
+
+type field struct {
+	key   string
+	value interface{} // string, []string or rawDate
+}
+
+// rawDate is a date written unquoted, so generators parse it as a date.
+type rawDate string
+
+func (g Generator) frontMatter(p Page) []field {
+	fields := []field{
+		{"title", p.Title},
+		{"slug", p.Slug},
+		{g.DateKey, rawDate(p.Date.Format(g.DateLayout))},
+	}
+	if p.Language != "" {
+		fields = append(fields, field{"lang", p.Language})
+	}
+	if p.Description != "" {
+		fields = append(fields, field{"description", p.Description})
+	}
+	if p.MetaTitle != "" {
+		fields = append(fields, field{"meta_title", p.MetaTitle})
+	}
+	if len(p.Tags) > 0 {
+		fields = append(fields, field{"tags", p.Tags})
+	}
+	return fields
+}
+
+func yamlValue(v interface{}) *yaml.Node {
+	switch v := v.(type) {
+	case []string:
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, s := range v {
+			seq.Content = append(seq.Content, yamlString(s))
+		}
+		return seq
+	case rawDate:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: string(v)}
+	default:
+		return yamlString(fmt.Sprint(v))
+	}
+}
+
+// yamlString double-quotes strings, so YAML 1.1 parsers such as Jekyll's
+// don't read a title like "yes" or "1.10" as a boolean or number.
+func yamlString(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: s}
+}
+
+func tomlValue(v interface{}) string {
+	switch v := v.(type) {
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = tomlString(s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	case rawDate:
+		return string(v)
+	default:
+		return tomlString(fmt.Sprint(v))
+	}
+}
+
+// tomlString quotes s as a TOML basic string.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+var titleLine = regexp.MustCompile(`^\s*#\s+[^\n]*\n?`)
+
+func stripTitle(body string) string {
+	return titleLine.ReplaceAllString(body, "")
+}
+
+// escapeMDX backslash-escapes {, } and < outside fenced code blocks.
+func escapeMDX(body string) string {
+	lines := strings.Split(body, "\n")
+	fenced := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if !fenced {
+			lines[i] = strings.NewReplacer("{", `\{`, "}", `\}`, "<", `\<`).Replace(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Excerpt returns the first paragraph of a Markdown body that isn't a
+// heading, on one line and cut at a word boundary to at most max runes.
+func Excerpt(body string, max int) string {
+	for _, para := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" || strings.HasPrefix(para, "#") {
+			continue
+		}
+		text := strings.Join(strings.Fields(para), " ")
+		if utf8.RuneCountInString(text) <= max {
+			return text
+		}
+		cut := string([]rune(text)[:max-1])
+		if i := strings.LastIndex(cut, " "); i > 0 {
+			cut = cut[:i]
+		}
+		return strings.TrimRight(cut, " ,.;:") + "…"
+	}
+	return ""
+}
+
+var metaLabel = regexp.MustCompile(`(?i)meta[ _-]?(title|description)\s*:\s*`)
+
+// ParseMeta extracts the title and description from a /meta-blog result,
+// which labels them "Meta title:" and "Meta description:". Without labels,
+// the first line is the title and the rest the description.
+func ParseMeta(text string) (title, description string) {
+	locs := metaLabel.FindAllStringSubmatchIndex(text, -1)
+	if len(locs) == 0 {
+		first, rest, _ := strings.Cut(strings.TrimSpace(text), "\n")
+		return strings.TrimSpace(first), strings.Join(strings.Fields(rest), " ")
+	}
+	for i, loc := range locs {
+		end := len(text)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		value := strings.Trim(strings.TrimSpace(text[loc[1]:end]), "|")
+		value = strings.Trim(strings.Join(strings.Fields(value), " "), `"`)
+		switch strings.ToLower(text[loc[2]:loc[3]]) {
+		case "title":
+			title = strings.TrimSpace(value)
+		case "description":
+			description = strings.TrimSpace(value)
+		}
+	}
+	return title, description
+}
@@ -0,0 +1,142 @@
+package site
+
+import (
+	"testing"
+	"time"
+)
+
+var testPage = Page{
+	Title:       `Yes: "Quoted"`,
+	Slug:        "yes",
+	Date:        time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+	Language:    "en",
+	Tags:        []string{"go", "1.10"},
+	Description: "A line\twith tab",
+	MetaTitle:   "Meta",
+	Body:        "# Yes\n\nFirst {para} <b>.\n\n```\n{code}\n```\n",
+}
+
+func TestRender(t *testing.T) {
+	for _, tc := range []struct {
+		gen, path, want string
+	}{
+		{"hugo", "site/content/posts/yes.md", `+++
+title = "Yes: \"Quoted\""
+slug = "yes"
+date = 2026-03-04T05:06:07Z
+lang = "en"
+description = "A line\twith tab"
+meta_title = "Meta"
+tags = ["go", "1.10"]
++++
+
+First {para} <b>.
+
+` + "```\n{code}\n```\n"},
+		{"jekyll", "site/_posts/2026-03-04-yes.md", `---
+title: "Yes: \"Quoted\""
+slug: "yes"
+date: 2026-03-04 05:06:07 +0000
+lang: "en"
+description: "A line\twith tab"
+meta_title: "Meta"
+tags: ["go", "1.10"]
+---
+
+First {para} <b>.
+
+` + "```\n{code}\n```\n"},
+		{"mdx", "site/src/content/blog/yes.mdx", `---
+title: "Yes: \"Quoted\""
+slug: "yes"
+pubDate: 2026-03-04
+lang: "en"
+description: "A line\twith tab"
+meta_title: "Meta"
+tags: ["go", "1.10"]
+---
+
+First \{para\} \<b>.
+
+` + "```\n{code}\n```\n"},
+	} {
+		g, err := Lookup(tc.gen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := g.Path("site", testPage); got != tc.path {
+			t.Errorf("%s: path %q, want %q", tc.gen, got, tc.path)
+		}
+		data, err := g.Render(testPage)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tc.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tc.gen, data, tc.want)
+		}
+	}
+}
+
+func TestRenderOmitsEmptyFields(t *testing.T) {
+	g, _ := Lookup("astro")
+	data, err := g.Render(Page{Title: "T", Slug: "t", Date: testPage.Date, Body: "Body"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "---\ntitle: \"T\"\nslug: \"t\"\npubDate: 2026-03-04\n---\n\nBody\n"
+	if string(data) != want {
+		t.Errorf("got\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestTOMLString(t *testing.T) {
+	for in, want := range map[string]string{
+		"plain":        `"plain"`,
+		`say "hi"`:     `"say \"hi\""`,
+		`C:\path`:      `"C:\\path"`,
+		"two\nlines":   `"two\nlines"`,
+		"bell\x07":     `"bell\u0007"`,
+		"del\x7f":      `"del\u007F"`,
+		"café ünïcode": `"café ünïcode"`,
+	} {
+		if got := tomlString(in); got != want {
+			t.Errorf("tomlString(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		max  int
+		want string
+	}{
+		{"# Title\n\nFirst  paragraph\nwrapped.\n\nSecond.", 160, "First paragraph wrapped."},
+		{"## Only\n\n\n\nText after blank lines.", 160, "Text after blank lines."},
+		{"One two three four five", 12, "One two…"},
+		{"Words, then more words", 10, "Words…"},
+		{"# Just a heading", 160, ""},
+		{"Windows\r\n\r\nline endings", 160, "Windows"},
+	} {
+		if got := Excerpt(tc.body, tc.max); got != tc.want {
+			t.Errorf("Excerpt(%q, %d) = %q, want %q", tc.body, tc.max, got, tc.want)
+		}
+	}
+}
+
+func TestParseMeta(t *testing.T) {
+	for _, tc := range []struct {
+		text, title, description string
+	}{
+		{"Meta title: Remote Work | Meta description: How teams stay in sync.", "Remote Work", "How teams stay in sync."},
+		{"META_TITLE: \"Quoted\"\nmeta-description:\n  Spread over\n  lines.", "Quoted", "Spread over lines."},
+		{"Meta description: first\nMeta title: second", "second", "first"},
+		{"A plain title\nand a description\non two lines", "A plain title", "and a description on two lines"},
+		{"  Title only  ", "Title only", ""},
+	} {
+		title, description := ParseMeta(tc.text)
+		if title != tc.title || description != tc.description {
+			t.Errorf("ParseMeta(%q) = %q, %q; want %q, %q", tc.text, title, description, tc.title, tc.description)
+		}
+	}
+}
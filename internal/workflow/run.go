@@ -0,0 +1,299 @@
+package workflow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/the20100/writesonic-cli/internal/output"
+)
+
+// Result is what one call produced: its texts (Markdown for landing pages)
+// and the raw API results.
+type Result struct {
+	Texts   []string        `json:"texts"`
+	Results json.RawMessage `json:"results"`
+}
+
+// Caller runs a command with fields keyed by its flag names, plus "engine",
+// "language" and "copies".
+type Caller func(ctx context.Context, command string, fields map[string]interface{}) (Result, error)
+
+// Runner executes workflows.
+type Runner struct {
+	Call  Caller
+	State *State
+	Log   io.Writer // progress, one line per call; nil for none
+
+	// Engine, Language and Copies apply to steps that don't set their own.
+	Engine   string
+	Language string
+	Copies   int
+
+	// Write saves a step's text to the path from its output template and
+	// returns the path written, or "" if it was skipped.
+	Write func(path string, data []byte) (string, error)
+}
+
+// StepOutput is a finished step.
+type StepOutput struct {
+	ID      string       `json:"id"`
+	Command string       `json:"command"`
+	Skipped bool         `json:"skipped,omitempty"`
+	Items   []ItemOutput `json:"items"`
+}
+
+// ItemOutput is one call of a step; steps without foreach have one.
+type ItemOutput struct {
+	Item    interface{}     `json:"item,omitempty"`
+	Skipped bool            `json:"skipped,omitempty"`
+	Reused  bool            `json:"reused,omitempty"` // answered from the state file
+	Texts   []string        `json:"texts,omitempty"`
+	Results json.RawMessage `json:"results,omitempty"`
+	File    string          `json:"file,omitempty"`
+}
+
+// StepError reports the step, and foreach item, a run failed at.
+type StepError struct {
+	Step  string
+	Index int // 1-based foreach index, 0 without foreach
+	Err   error
+}
+
+func (e *StepError) Error() string {
+	if e.Index > 0 {
+		return fmt.Sprintf("step %q, item %d: %v", e.Step, e.Index, e.Err)
+	}
+	return fmt.Sprintf("step %q: %v", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error { return e.Err }
+
+// Run executes the steps in order. Calls recorded in the state file with
+// identical inputs are reused instead of sent again. The outputs of the
+// steps that finished are returned even on error.
+func (r *Runner) Run(ctx context.Context, w *Workflow) ([]StepOutput, error) {
+	steps := map[string]interface{}{}
+	var outputs []StepOutput
+	for _, step := range w.Steps {
+		data := map[string]interface{}{"vars": w.Vars, "steps": steps}
+		out, err := r.runStep(ctx, step, data)
+		if err != nil {
+			return outputs, err
+		}
+		outputs = append(outputs, out)
+		steps[step.ID] = out.templateData()
+	}
+	return outputs, nil
+}
+
+func (r *Runner) runStep(ctx context.Context, step Step, data map[string]interface{}) (StepOutput, error) {
+	out := StepOutput{ID: step.ID, Command: step.Command, Skipped: true}
+	items := []interface{}{nil}
+	if step.Foreach != nil {
+		var err error
+		if items, err = foreachItems(step.Foreach, data); err != nil {
+			return out, &StepError{Step: step.ID, Err: fmt.Errorf("foreach: %w", err)}
+		}
+	}
+
+	for i, item := range items {
+		d := map[string]interface{}{"vars": data["vars"], "steps": data["steps"]}
+		index := 0
+		if step.Foreach != nil {
+			index = i + 1
+			d["item"], d["index"] = item, index
+		}
+		fail := func(err error) (StepOutput, error) {
+			return out, &StepError{Step: step.ID, Index: index, Err: err}
+		}
+		label := step.ID
+		if index > 0 {
+			label = fmt.Sprintf("%s [%d/%d]", step.ID, index, len(items))
+		}
+
+		it := ItemOutput{Item: item}
+		if step.If != "" {
+			cond, err := render(step.If, d)
+			if err != nil {
+				return fail(fmt.Errorf("if: %w", err))
+			}
+			if !truthy(cond) {
+				r.logf("%s: skipped (if is false)\n", label)
+				it.Skipped = true
+				out.Items = append(out.Items, it)
+				continue
+			}
+		}
+		out.Skipped = false
+
+		fields, err := r.fields(step, d)
+		if err != nil {
+			return fail(err)
+		}
+		key := callKey(step.ID, step.Command, fields)
+		res, ok := r.State.Calls[key]
+		if ok {
+			r.logf("%s: %s (reused)\n", label, step.Command)
+			it.Reused = true
+		} else {
+			r.logf("%s: %s\n", label, step.Command)
+			if res, err = r.Call(ctx, step.Command, fields); err != nil {
+				return fail(err)
+			}
+			r.State.Calls[key] = res
+			if err := r.State.Save(); err != nil {
+				return fail(fmt.Errorf("save state: %w", err))
+			}
+		}
+		it.Texts, it.Results = res.Texts, res.Results
+
+		if step.Output != "" {
+			d["text"] = first(res.Texts)
+			path, err := render(step.Output, d)
+			if err != nil {
+				return fail(fmt.Errorf("output: %w", err))
+			}
+			text := strings.TrimRight(strings.Join(res.Texts, "\n\n"), "\n") + "\n"
+			if it.File, err = r.Write(strings.TrimSpace(path), []byte(text)); err != nil {
+				return fail(fmt.Errorf("output: %w", err))
+			}
+		}
+		out.Items = append(out.Items, it)
+	}
+	return out, nil
+}
+
+// fields renders a step's flags and adds the engine, language and copies
+// it runs with.
+func (r *Runner) fields(step Step, d map[string]interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	for name, v := range step.With {
+		rendered, err := renderValue(v, d)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		fields[name] = rendered
+	}
+	engine, err := render(step.Engine, d)
+	if err != nil {
+		return nil, fmt.Errorf("engine: %w", err)
+	}
+	language, err := render(step.Language, d)
+	if err != nil {
+		return nil, fmt.Errorf("language: %w", err)
+	}
+	copies := step.Copies
+	if engine = strings.TrimSpace(engine); engine == "" {
+		engine = r.Engine
+	}
+	if language = strings.TrimSpace(language); language == "" {
+		language = r.Language
+	}
+	if copies == 0 {
+		copies = r.Copies
+	}
+	if engine != "" {
+		fields["engine"] = engine
+	}
+	if language != "" {
+		fields["language"] = language
+	}
+	if copies != 0 {
+		fields["copies"] = strconv.Itoa(copies)
+	}
+	return fields, nil
+}
+
+func (r *Runner) logf(format string, args ...interface{}) {
+	if r.Log != nil {
+		fmt.Fprintf(r.Log, format, args...)
+	}
+}
+
+// templateData is what later steps see as .steps.ID.
+func (o StepOutput) templateData() map[string]interface{} {
+	texts := []string{}
+	results := []interface{}{}
+	items := []interface{}{}
+	for _, it := range o.Items {
+		var raw []interface{}
+		if len(it.Results) > 0 {
+			json.Unmarshal(it.Results, &raw)
+		}
+		texts = append(texts, it.Texts...)
+		results = append(results, raw...)
+		items = append(items, map[string]interface{}{
+			"item":    it.Item,
+			"skipped": it.Skipped,
+			"text":    first(it.Texts),
+			"texts":   it.Texts,
+			"results": raw,
+		})
+	}
+	return map[string]interface{}{
+		"skipped": o.Skipped,
+		"text":    first(texts),
+		"texts":   texts,
+		"results": results,
+		"items":   items,
+	}
+}
+
+func first(texts []string) string {
+	if len(texts) == 0 {
+		return ""
+	}
+	return texts[0]
+}
+
+// callKey identifies a call by step and inputs, so an edited step or a
+// changed earlier output runs again on resume.
+func callKey(step, command string, fields map[string]interface{}) string {
+	data, _ := json.Marshal([]interface{}{step, command, fields})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// State is the record of completed calls, kept in a JSON file.
+type State struct {
+	Workflow string            `json:"workflow"`
+	Calls    map[string]Result `json:"calls"`
+
+	path string
+}
+
+// LoadState reads the state file at path; a missing file is an empty state.
+func LoadState(path, workflow string) (*State, error) {
+	s := &State{Workflow: workflow, Calls: map[string]Result{}, path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("read state %s: %w (delete it or use --restart)", path, err)
+	}
+	if s.Calls == nil {
+		s.Calls = map[string]Result{}
+	}
+	return s, nil
+}
+
+// Save writes the state file atomically.
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return output.WriteFileAtomic(s.path, append(data, '\n'), 0o600)
+}
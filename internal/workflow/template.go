@@ -0,0 +1,170 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Templates see:
+//
+//	.vars.NAME            workflow variables
+//	.steps.ID.text        first text of an earlier step
+//	.steps.ID.texts       all its texts (every copy, every item)
+//	.steps.ID.results     its raw API results
+//	.steps.ID.items       per-item outputs of a foreach step
+//	.steps.ID.skipped     whether the step was skipped
+//	.item, .index         the current foreach item and its 1-based index
+
+var funcs = template.FuncMap{
+	"join":  func(sep string, v interface{}) string { return strings.Join(toStrings(v), sep) },
+	"split": func(sep, s string) []string { return strings.Split(s, sep) },
+	"lines": lines,
+	"trim":  strings.TrimSpace,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"default": func(def, v interface{}) interface{} {
+		if v == nil || fmt.Sprint(v) == "" {
+			return def
+		}
+		return v
+	},
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("").Funcs(funcs).Option("missingkey=error").Parse(text)
+}
+
+// render executes a template against data.
+func render(text string, data map[string]interface{}) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	t, err := parseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// renderValue renders a string, or each string in a list; other values pass
+// through.
+func renderValue(v interface{}, data map[string]interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return render(v, data)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			r, err := renderValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// truthy reports whether a rendered condition holds.
+func truthy(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "false", "0", "no", "off", "<no value>":
+		return false
+	}
+	return true
+}
+
+var varPath = regexp.MustCompile(`^\.?[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// foreachItems resolves a foreach value: a YAML list (strings templated), a
+// path such as vars.features or steps.ideas.texts, or a template whose
+// output is split into lines.
+func foreachItems(v interface{}, data map[string]interface{}) ([]interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		r, err := renderValue(v, data)
+		if err != nil {
+			return nil, err
+		}
+		return r.([]interface{}), nil
+	case string:
+		s := strings.TrimSpace(v)
+		if varPath.MatchString(s) {
+			found, err := lookup(data, strings.TrimPrefix(s, "."))
+			if err != nil {
+				return nil, err
+			}
+			switch found := found.(type) {
+			case []interface{}:
+				return found, nil
+			case []string:
+				return toItems(found), nil
+			case string:
+				return toItems(lines(found)), nil
+			}
+			return []interface{}{found}, nil
+		}
+		out, err := render(s, data)
+		if err != nil {
+			return nil, err
+		}
+		return toItems(lines(out)), nil
+	}
+	return nil, fmt.Errorf("foreach must be a list, a variable path or a template")
+}
+
+func lookup(data map[string]interface{}, path string) (interface{}, error) {
+	var cur interface{} = data
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: %q is not a map", path, key)
+		}
+		if cur, ok = m[key]; !ok {
+			return nil, fmt.Errorf("%s: no %q", path, key)
+		}
+	}
+	return cur, nil
+}
+
+// lines splits s into trimmed, non-empty lines.
+func lines(s string) []string {
+	var out []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func toItems(list []string) []interface{} {
+	out := make([]interface{}, len(list))
+	for i, s := range list {
+		out[i] = s
+	}
+	return out
+}
+
+func toStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, len(v))
+		for i, item := range v {
+			out[i] = fmt.Sprint(item)
+		}
+		return out
+	case nil:
+		return nil
+	}
+	return []string{fmt.Sprint(v)}
+}
@@ -0,0 +1,135 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	data := map[string]interface{}{
+		"vars": map[string]interface{}{"product": "Acme", "features": []interface{}{"fast", "cheap"}},
+		"steps": map[string]interface{}{
+			"ideas": map[string]interface{}{"text": "  First idea  ", "texts": []string{"a", "b"}},
+		},
+	}
+	tests := []struct{ in, want string }{
+		{"plain text", "plain text"},
+		{"{{.vars.product}} rocks", "Acme rocks"},
+		{`{{join ", " .vars.features}}`, "fast, cheap"},
+		{"{{trim .steps.ideas.text | upper}}", "FIRST IDEA"},
+		{`{{join "|" .steps.ideas.texts}}`, "a|b"},
+		{`{{default "none" .vars.missing_ok}}`, "none"},
+	}
+	data["vars"].(map[string]interface{})["missing_ok"] = ""
+	for _, tt := range tests {
+		got, err := render(tt.in, data)
+		if err != nil {
+			t.Errorf("render(%q): %v", tt.in, err)
+		} else if got != tt.want {
+			t.Errorf("render(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if _, err := render("{{.vars.nope}}", data); err == nil {
+		t.Error("missing variable rendered without error")
+	}
+}
+
+func TestTruthy(t *testing.T) {
+	for s, want := range map[string]bool{
+		"": false, "false": false, " 0 ": false, "No": false, "off": false, "<no value>": false,
+		"true": true, "yes": true, "1": true, "anything": true,
+	} {
+		if got := truthy(s); got != want {
+			t.Errorf("truthy(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestForeachItems(t *testing.T) {
+	data := map[string]interface{}{
+		"vars": map[string]interface{}{"features": []interface{}{"x", "y"}, "list": "one\n\n two \n"},
+		"steps": map[string]interface{}{
+			"ideas": map[string]interface{}{"texts": []string{"i1", "i2", "i3"}},
+		},
+	}
+	tests := []struct {
+		in   interface{}
+		want []interface{}
+	}{
+		{[]interface{}{"{{index .vars.features 0}}", "lit"}, []interface{}{"x", "lit"}},
+		{"vars.features", []interface{}{"x", "y"}},
+		{".steps.ideas.texts", []interface{}{"i1", "i2", "i3"}},
+		{"vars.list", []interface{}{"one", "two"}},
+		{`{{range .vars.features}}{{.}}-item{{"\n"}}{{end}}`, []interface{}{"x-item", "y-item"}},
+	}
+	for _, tt := range tests {
+		got, err := foreachItems(tt.in, data)
+		if err != nil {
+			t.Errorf("foreachItems(%v): %v", tt.in, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("foreachItems(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	if _, err := foreachItems("vars.nope", data); err == nil {
+		t.Error("unknown path accepted")
+	}
+}
+
+func TestRunChainsStepsAndResumes(t *testing.T) {
+	w := &Workflow{
+		Vars: map[string]interface{}{"topic": "remote work"},
+		Steps: []Step{
+			{ID: "ideas", Command: "blog-ideas", With: map[string]interface{}{"topic": "{{.vars.topic}}"}},
+			{ID: "intros", Command: "write paragraph", Foreach: "steps.ideas.texts",
+				With: map[string]interface{}{"topic": "{{.item}} ({{.index}})"}},
+			{ID: "never", Command: "write conclusion", If: "{{.steps.ideas.skipped}}",
+				With: map[string]interface{}{"topic": "x"}},
+		},
+	}
+	var calls []string
+	call := func(ctx context.Context, command string, fields map[string]interface{}) (Result, error) {
+		calls = append(calls, fmt.Sprintf("%s: %s", command, fields["topic"]))
+		if command == "blog-ideas" {
+			return Result{Texts: []string{"Idea A", "Idea B"}}, nil
+		}
+		return Result{Texts: []string{"about " + fields["topic"].(string)}}, nil
+	}
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	run := func() []StepOutput {
+		state, err := LoadState(statePath, "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := &Runner{Call: call, State: state, Engine: "good"}
+		out, err := r.Run(context.Background(), w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	out := run()
+	want := []string{"blog-ideas: remote work", "write paragraph: Idea A (1)", "write paragraph: Idea B (2)"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+	if len(out) != 3 || !out[2].Skipped || out[1].Items[1].Texts[0] != "about Idea B (2)" {
+		t.Errorf("outputs = %+v", out)
+	}
+
+	calls = nil
+	out = run()
+	if len(calls) != 0 {
+		t.Errorf("resumed run called %q again", calls)
+	}
+	if !out[1].Items[0].Reused {
+		t.Error("resumed item not marked reused")
+	}
+	if got := strings.Join(out[1].Items[0].Texts, ""); got != "about Idea A (1)" {
+		t.Errorf("reused text = %q", got)
+	}
+}
@@ -0,0 +1,148 @@
+// Package workflow runs declarative multi-step generation workflows: a YAML
+// file lists steps that each call a CLI command, with inputs templated from
+// variables and earlier steps' outputs, optional foreach loops and
+// conditions, and per-step engine and language overrides. Every completed
+// call is saved to a state file, so a failed run resumes where it stopped.
+package workflow
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workflow is a parsed workflow file.
+type Workflow struct {
+	Name     string                 `yaml:"name"`
+	Vars     map[string]interface{} `yaml:"vars"`
+	Engine   string                 `yaml:"engine"`   // default engine for every step
+	Language string                 `yaml:"language"` // default language for every step
+	Steps    []Step                 `yaml:"steps"`
+}
+
+// Step is one call, or one call per item with Foreach.
+type Step struct {
+	ID       string                 `yaml:"id"`
+	Command  string                 `yaml:"command"`  // e.g. "copy pas"
+	With     map[string]interface{} `yaml:"with"`     // the command's flags, templated
+	Foreach  interface{}            `yaml:"foreach"`  // list, variable path or template
+	If       string                 `yaml:"if"`       // template; false, 0, no or empty skips
+	Engine   string                 `yaml:"engine"`   // templated
+	Language string                 `yaml:"language"` // templated
+	Copies   int                    `yaml:"copies"`
+	Output   string                 `yaml:"output"` // file path template for the step's text
+}
+
+var stepID = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Load reads and parses a workflow file, rejecting unknown keys, duplicate
+// or malformed step IDs and invalid templates.
+func Load(path string) (*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var w Workflow
+	if err := dec.Decode(&w); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if len(w.Steps) == 0 {
+		return nil, fmt.Errorf("%s: no steps", path)
+	}
+	if w.Vars == nil {
+		w.Vars = map[string]interface{}{}
+	}
+
+	seen := map[string]bool{}
+	for i, s := range w.Steps {
+		if !stepID.MatchString(s.ID) {
+			return nil, fmt.Errorf("step %d: id %q must be letters, digits and underscores, not starting with a digit", i+1, s.ID)
+		}
+		if seen[s.ID] {
+			return nil, fmt.Errorf("step %q: duplicate id", s.ID)
+		}
+		seen[s.ID] = true
+		w.Steps[i].Command = strings.Join(strings.Fields(s.Command), " ")
+		if err := s.checkTemplates(); err != nil {
+			return nil, fmt.Errorf("step %q: %w", s.ID, err)
+		}
+	}
+	return &w, nil
+}
+
+// Check verifies every step's command and flag names. fields returns the
+// flags a command accepts, or an error for an unknown command.
+func (w *Workflow) Check(fields func(command string) ([]string, error)) error {
+	for _, s := range w.Steps {
+		known, err := fields(s.Command)
+		if err != nil {
+			return fmt.Errorf("step %q: %w", s.ID, err)
+		}
+		for name := range s.With {
+			if !contains(known, name) {
+				sort.Strings(known)
+				return fmt.Errorf("step %q: %s has no flag %q (use %s)", s.ID, s.Command, name, strings.Join(known, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// SetVars overrides variables with NAME=VALUE pairs.
+func (w *Workflow) SetVars(pairs []string) error {
+	for _, p := range pairs {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid variable %q (want NAME=VALUE)", p)
+		}
+		w.Vars[name] = value
+	}
+	return nil
+}
+
+func (s Step) checkTemplates() error {
+	texts := []string{s.If, s.Engine, s.Language, s.Output}
+	for _, v := range s.With {
+		texts = append(texts, templateStrings(v)...)
+	}
+	texts = append(texts, templateStrings(s.Foreach)...)
+	for _, t := range texts {
+		if _, err := parseTemplate(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateStrings returns the strings in a YAML value that are templated.
+func templateStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package writesonic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Cache stores API responses under a request fingerprint, so identical
+// requests are answered without being billed again. Put is only called with
+// successful responses; path is the endpoint, for bookkeeping.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Put(key, path string, response []byte) error
+}
+
+// fingerprint returns the cache key for a request: a SHA-256 over the API
+// key, base URL, endpoint path, sorted query params and canonical JSON body.
+// Including the key keeps accounts from being served each other's responses;
+// only the hash is stored, never the key itself.
+func fingerprint(apiKey, baseURL, path string, params url.Values, body map[string]interface{}) (string, error) {
+	// json.Marshal sorts map keys, so the body encoding is canonical.
+	b, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("marshal body: %w", err)
+	}
+	h := sha256.New()
+	for _, part := range []string{apiKey, baseURL, path, params.Encode(), string(b)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,418 @@
+// Package writesonic is a Go client for the Writesonic API. It is the library
+// the writesonic CLI is built on: every endpoint has a typed request struct
+// and a method on Client, and engine, language and copies are set per call.
+//
+//	c := writesonic.NewClient(os.Getenv("WRITESONIC_API_KEY"))
+//	ideas, err := c.BlogIdeas(ctx, writesonic.BlogIdeasRequest{Topic: "remote work"},
+//		writesonic.WithEngine("premium"), writesonic.WithCopies(3))
+package writesonic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the production Writesonic content API.
+const DefaultBaseURL = "https://api.writesonic.com/v2/business/content"
+
+// Client calls the Writesonic API. It is safe for concurrent use.
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	retry      RetryPolicy
+	timeout    time.Duration
+	onRetry    func(attempt int, wait time.Duration, err error)
+	observers  []func(Exchange)
+	cache      Cache
+	validate   bool
+}
+
+// Exchange describes a successful API call, as passed to observers.
+type Exchange struct {
+	Path     string
+	Params   url.Values
+	Body     map[string]interface{}
+	Response []byte
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL points the client at a different API root, such as a staging
+// gateway or a local mock server. A trailing slash is ignored.
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		if u != "" {
+			c.baseURL = strings.TrimRight(u, "/")
+		}
+	}
+}
+
+// WithTransport replaces the HTTP transport, e.g. one set up for a proxy or
+// mTLS. Errors it returns are retried like network failures unless they have
+// a Retryable method that reports false.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = rt
+	}
+}
+
+// WithRetryPolicy sets the retry policy used for transient failures.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithRetryNotify registers a callback invoked before each retry.
+func WithRetryNotify(fn func(attempt int, wait time.Duration, err error)) Option {
+	return func(c *Client) {
+		c.onRetry = fn
+	}
+}
+
+// WithTimeout bounds each individual request attempt. Zero means no limit
+// beyond the caller's context.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithObserver registers fn to be called after every successful request,
+// e.g. to record history. Observers run synchronously on the calling goroutine.
+func WithObserver(fn func(Exchange)) Option {
+	return func(c *Client) {
+		c.observers = append(c.observers, fn)
+	}
+}
+
+// WithCache serves identical requests from cache and stores new responses.
+// A nil cache disables caching.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithValidation turns client-side request validation on or off. It is on by
+// default; turn it off to use API capabilities the CLI doesn't know about yet.
+func WithValidation(on bool) Option {
+	return func(c *Client) {
+		c.validate = on
+	}
+}
+
+// NewClient returns a client authenticated with apiKey.
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
+		apiKey:     apiKey,
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{},
+		retry:      DefaultRetryPolicy(),
+		validate:   true,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Post sends an authenticated POST request to the given path with query params
+// and a JSON body. Transient failures (network errors, 429, 502, 503, 504) are
+// retried according to the client's RetryPolicy. Cancelling ctx aborts both
+// in-flight requests and pending retries. Unless disabled with WithValidation,
+// the request is checked with ValidateRequest before anything is sent.
+// Returns the raw response bytes.
+func (c *Client) Post(ctx context.Context, path string, queryParams url.Values, body map[string]interface{}) ([]byte, error) {
+	data, _, err := c.post(ctx, path, queryParams, body)
+	return data, err
+}
+
+type skipCacheKey struct{}
+
+// WithoutCache returns a context whose requests are always sent, even when an
+// identical one is cached; the fresh response still replaces the cached one.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipCacheKey{}, true)
+}
+
+func skipCache(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipCacheKey{}).(bool)
+	return skip
+}
+
+// post is Post, additionally reporting whether the response came from cache.
+// Cache hits are not passed to observers since nothing new was generated.
+func (c *Client) post(ctx context.Context, path string, queryParams url.Values, body map[string]interface{}) ([]byte, bool, error) {
+	if c.validate {
+		if err := ValidateRequest(path, queryParams, body); err != nil {
+			return nil, false, err
+		}
+	}
+	var cacheKey string
+	if c.cache != nil {
+		key, err := fingerprint(c.apiKey, c.baseURL, path, queryParams, body)
+		if err != nil {
+			return nil, false, err
+		}
+		if data, ok := c.cache.Get(key); ok && !skipCache(ctx) {
+			return data, true, nil
+		}
+		cacheKey = key
+	}
+
+	data, err := c.send(ctx, path, queryParams, body)
+	if err != nil {
+		return nil, false, err
+	}
+	if cacheKey != "" {
+		// A cache write failure only costs a future re-request.
+		_ = c.cache.Put(cacheKey, path, data)
+	}
+	for _, fn := range c.observers {
+		fn(Exchange{Path: path, Params: queryParams, Body: body, Response: data})
+	}
+	return data, false, nil
+}
+
+// send performs the request with retries.
+func (c *Client) send(ctx context.Context, path string, queryParams url.Values, body map[string]interface{}) ([]byte, error) {
+	endpoint := c.baseURL + path + "?" + queryParams.Encode()
+
+	var payload []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal body: %w", err)
+		}
+		payload = b
+	}
+
+	attempts := c.retry.attempts()
+	for attempt := 1; ; attempt++ {
+		data, err := c.do(ctx, path, endpoint, payload)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("request cancelled: %w", ctx.Err())
+		}
+		if err == nil {
+			return data, nil
+		}
+		if !retryable(err) || attempt >= attempts {
+			return nil, err
+		}
+		var retryAfter string
+		var ae *APIError
+		if errors.As(err, &ae) {
+			retryAfter = ae.retryAfter
+		}
+		wait := c.retry.delay(attempt, retryAfter)
+		if c.onRetry != nil {
+			c.onRetry(attempt, wait, err)
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, fmt.Errorf("request cancelled: %w", ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+// retryable reports whether a failed attempt is worth repeating. A transport
+// can rule out retries by returning an error with a Retryable method that
+// reports false, as a replayer does for a request it has no answer for.
+func retryable(err error) bool {
+	var r interface{ Retryable() bool }
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+	var ne *NetworkError
+	if errors.As(err, &ne) {
+		return true
+	}
+	var ae *APIError
+	if errors.As(err, &ae) {
+		return retryableStatus(ae.StatusCode)
+	}
+	return false
+}
+
+// do performs a single request attempt. Failures are returned as
+// *NetworkError or *APIError.
+func (c *Client) do(ctx context.Context, path, endpoint string, payload []byte) ([]byte, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, c.networkError(path, "execute request", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, c.networkError(path, "read response", err)
+	}
+
+	if resp.StatusCode != 200 {
+		requestID := resp.Header.Get("X-Request-Id")
+		if requestID == "" {
+			requestID = resp.Header.Get("Request-Id")
+		}
+		ae := newAPIError(resp.StatusCode, path, requestID, data)
+		ae.retryAfter = resp.Header.Get("Retry-After")
+		return nil, ae
+	}
+
+	return data, nil
+}
+
+func (c *Client) networkError(path, op string, err error) *NetworkError {
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("request timed out after %s: %w", c.timeout, err)
+	} else {
+		err = fmt.Errorf("%s: %w", op, err)
+	}
+	return &NetworkError{Endpoint: path, Err: err}
+}
+
+// postResults sends a POST and decodes the response into a slice of Result.
+func (c *Client) postResults(ctx context.Context, path string, queryParams url.Values, body map[string]interface{}) ([]Result, error) {
+	data, cached, err := c.post(ctx, path, queryParams, body)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	for i := range results {
+		results[i].Cached = cached
+	}
+	return results, nil
+}
+
+// postLandingPages sends a POST and decodes into a slice of LandingPage.
+func (c *Client) postLandingPages(ctx context.Context, queryParams url.Values, body map[string]interface{}) ([]LandingPage, error) {
+	data, cached, err := c.post(ctx, "/landing-pages", queryParams, body)
+	if err != nil {
+		return nil, err
+	}
+	var results []LandingPage
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	for i := range results {
+		results[i].Cached = cached
+	}
+	return results, nil
+}
+
+// Params are the query options shared by every endpoint. Zero fields are
+// left out so the API default applies.
+type Params struct {
+	Engine   string
+	Language string
+	Copies   int
+}
+
+// Values encodes p as query parameters.
+func (p Params) Values() url.Values {
+	v := url.Values{}
+	if p.Engine != "" {
+		v.Set("engine", p.Engine)
+	}
+	if p.Language != "" {
+		v.Set("language", p.Language)
+	}
+	if p.Copies > 0 {
+		v.Set("num_copies", strconv.Itoa(p.Copies))
+	}
+	return v
+}
+
+// CallOption sets one of the Params of a single call.
+type CallOption func(*Params)
+
+// WithEngine selects the quality tier: economy, average, good or premium.
+func WithEngine(engine string) CallOption {
+	return func(p *Params) { p.Engine = engine }
+}
+
+// WithLanguage selects the output language by code, e.g. "fr" or "pt-br".
+func WithLanguage(lang string) CallOption {
+	return func(p *Params) { p.Language = lang }
+}
+
+// WithCopies asks for n variations (1 to MaxCopies).
+func WithCopies(n int) CallOption {
+	return func(p *Params) { p.Copies = n }
+}
+
+// WithParams sets all three options at once.
+func WithParams(params Params) CallOption {
+	return func(p *Params) { *p = params }
+}
+
+func apply(opts []CallOption) url.Values {
+	var p Params
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p.Values()
+}
+
+// Call sends req, a request struct or a map of body fields, to an endpoint
+// that returns text results. It covers endpoints without a typed method.
+func (c *Client) Call(ctx context.Context, path string, req interface{}, opts ...CallOption) ([]Result, error) {
+	body, err := toBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.postResults(ctx, path, apply(opts), body)
+}
+
+// toBody converts a request struct to the JSON object the API expects, so
+// validation, caching and history see the same body as the server.
+func toBody(req interface{}) (map[string]interface{}, error) {
+	if m, ok := req.(map[string]interface{}); ok {
+		return m, nil
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,176 @@
+package writesonic
+
+import "context"
+
+// BlogIdeasRequest is the input of Client.BlogIdeas.
+type BlogIdeasRequest struct {
+	Topic          string `json:"topic"`
+	PrimaryKeyword string `json:"primary_keyword,omitempty"`
+}
+
+// BlogIdeas generates blog post ideas for a topic.
+func (c *Client) BlogIdeas(ctx context.Context, req BlogIdeasRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/blog-ideas", req, opts...)
+}
+
+// ArticleRequest is the input of Client.Article.
+type ArticleRequest struct {
+	Title    string   `json:"article_title"`
+	Intro    string   `json:"article_intro"`
+	Sections []string `json:"article_sections"`
+}
+
+// Article writes a long-form SEO article from a title, intro and section
+// headings (AI Article Writer v3).
+func (c *Client) Article(ctx context.Context, req ArticleRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/ai-article-writer-v3", req, opts...)
+}
+
+// InstantArticleRequest is the input of Client.InstantArticle.
+type InstantArticleRequest struct {
+	Title string `json:"article_title"`
+}
+
+// InstantArticle writes a roughly 1500-word article from a title.
+func (c *Client) InstantArticle(ctx context.Context, req InstantArticleRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/instant-article-writer", req, opts...)
+}
+
+// LandingPageRequest is the input of Client.LandingPage.
+type LandingPageRequest struct {
+	ProductName        string `json:"product_name"`
+	ProductDescription string `json:"product_description"`
+	Feature1           string `json:"feature_1"`
+	Feature2           string `json:"feature_2"`
+	Feature3           string `json:"feature_3"`
+}
+
+// LandingPage generates full landing page copy: titles, features and CTA.
+func (c *Client) LandingPage(ctx context.Context, req LandingPageRequest, opts ...CallOption) ([]LandingPage, error) {
+	body, err := toBody(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.postLandingPages(ctx, apply(opts), body)
+}
+
+// ProductRequest is the input of the product copy endpoints: LandingHeadlines,
+// PAS and AIDA.
+type ProductRequest struct {
+	ProductName        string `json:"product_name"`
+	ProductDescription string `json:"product_description"`
+}
+
+// LandingHeadlines generates landing page headlines.
+func (c *Client) LandingHeadlines(ctx context.Context, req ProductRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/landing-page-headlines", req, opts...)
+}
+
+// PAS writes Pain-Agitate-Solution copy.
+func (c *Client) PAS(ctx context.Context, req ProductRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/pas", req, opts...)
+}
+
+// AIDA writes Attention-Interest-Desire-Action copy.
+func (c *Client) AIDA(ctx context.Context, req ProductRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/aida", req, opts...)
+}
+
+// CallToActionRequest is the input of Client.CallToAction.
+type CallToActionRequest struct {
+	ProductName string `json:"product_name"`
+}
+
+// CallToAction generates calls to action for a product.
+func (c *Client) CallToAction(ctx context.Context, req CallToActionRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/call-to-action", req, opts...)
+}
+
+// BulletAnswersRequest is the input of Client.BulletAnswers.
+type BulletAnswersRequest struct {
+	Question string `json:"question"`
+}
+
+// BulletAnswers answers a question as bullet points.
+func (c *Client) BulletAnswers(ctx context.Context, req BulletAnswersRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/bulletpoint-answers", req, opts...)
+}
+
+// RephraseRequest is the input of Client.Rephrase. Content must be 20-1000
+// characters.
+type RephraseRequest struct {
+	Content string `json:"content_to_rephrase"`
+	Tone    string `json:"tone_of_voice,omitempty"`
+}
+
+// Rephrase rewrites content in different words.
+func (c *Client) Rephrase(ctx context.Context, req RephraseRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/content-rephrase", req, opts...)
+}
+
+// ShortenRequest is the input of Client.Shorten. Content must be 20-1000
+// characters.
+type ShortenRequest struct {
+	Content string `json:"content_to_shorten"`
+	Tone    string `json:"tone_of_voice,omitempty"`
+}
+
+// Shorten condenses content while keeping its message.
+func (c *Client) Shorten(ctx context.Context, req ShortenRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/content-shorten", req, opts...)
+}
+
+// ChangeToneRequest is the input of Client.ChangeTone.
+type ChangeToneRequest struct {
+	Content string `json:"content_to_change"`
+	Tone    string `json:"tone"`
+}
+
+// ChangeTone rewrites content in the given tone, e.g. "formal".
+func (c *Client) ChangeTone(ctx context.Context, req ChangeToneRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/tone-changer", req, opts...)
+}
+
+// KeywordsRequest is the input of Client.RewriteWithKeywords. Keywords is a
+// comma-separated list.
+type KeywordsRequest struct {
+	Content  string `json:"content"`
+	Keywords string `json:"keywords"`
+}
+
+// RewriteWithKeywords rewrites content to include target SEO keywords.
+func (c *Client) RewriteWithKeywords(ctx context.Context, req KeywordsRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/rewrite-with-keywords", req, opts...)
+}
+
+// ParagraphRequest is the input of Client.Paragraph.
+type ParagraphRequest struct {
+	Topic        string `json:"topic"`
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// Paragraph writes a paragraph about a topic.
+func (c *Client) Paragraph(ctx context.Context, req ParagraphRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/paragraph-writer", req, opts...)
+}
+
+// MetaBlogRequest is the input of Client.MetaBlog.
+type MetaBlogRequest struct {
+	Title       string `json:"blog_title"`
+	Description string `json:"blog_description"`
+}
+
+// MetaBlog generates an SEO meta title and description for a blog post.
+func (c *Client) MetaBlog(ctx context.Context, req MetaBlogRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/meta-blog", req, opts...)
+}
+
+// ConclusionRequest is the input of Client.Conclusion.
+type ConclusionRequest struct {
+	Topic string `json:"topic"`
+}
+
+// Conclusion writes a conclusion for an article on a topic.
+func (c *Client) Conclusion(ctx context.Context, req ConclusionRequest, opts ...CallOption) ([]Result, error) {
+	return c.Call(ctx, "/conclusion-writer", req, opts...)
+}
@@ -0,0 +1,156 @@
+package writesonic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorClass groups API failures by how a caller should react to them.
+type ErrorClass int
+
+const (
+	ClassUnknown    ErrorClass = iota
+	ClassAuth                  // 401, 403: bad or missing API key
+	ClassQuota                 // 402, 429: out of credits or rate limited
+	ClassValidation            // 422: request rejected by the API
+	ClassServer                // 5xx: failure on Writesonic's side
+	ClassNetwork               // connection, DNS, TLS or timeout failure
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ClassAuth:
+		return "auth"
+	case ClassQuota:
+		return "quota"
+	case ClassValidation:
+		return "validation"
+	case ClassServer:
+		return "server"
+	case ClassNetwork:
+		return "network"
+	}
+	return "unknown"
+}
+
+// APIError is returned when the API answers with a non-200 status.
+type APIError struct {
+	StatusCode int    `json:"status"`
+	Endpoint   string `json:"endpoint"`
+	RequestID  string `json:"request_id,omitempty"`
+	// Body is the decoded JSON response, or the raw text if it wasn't JSON.
+	Body any `json:"body,omitempty"`
+	// Validation is set for 422 responses with a decodable detail list.
+	Validation *ValidationError `json:"-"`
+
+	retryAfter string
+}
+
+// newAPIError builds an APIError from a failed response.
+func newAPIError(status int, endpoint, requestID string, data []byte) *APIError {
+	e := &APIError{StatusCode: status, Endpoint: endpoint, RequestID: requestID}
+	var decoded any
+	if json.Unmarshal(data, &decoded) == nil {
+		e.Body = decoded
+	} else if s := strings.TrimSpace(string(data)); s != "" {
+		e.Body = s
+	}
+	if status == 422 {
+		var ve ValidationError
+		if json.Unmarshal(data, &ve) == nil && len(ve.Detail) > 0 {
+			e.Validation = &ve
+		}
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "API error %d on %s", e.StatusCode, e.Endpoint)
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request %s)", e.RequestID)
+	}
+	switch {
+	case e.Validation != nil:
+		b.WriteString(": ")
+		b.WriteString(e.Validation.Error())
+	case e.Body != nil:
+		b.WriteString(": ")
+		b.WriteString(e.message())
+	}
+	return b.String()
+}
+
+// Unwrap exposes the ValidationError so errors.As keeps working for 422s.
+func (e *APIError) Unwrap() error {
+	if e.Validation == nil {
+		return nil
+	}
+	return e.Validation
+}
+
+// Class returns the error class for the response status.
+func (e *APIError) Class() ErrorClass {
+	switch {
+	case e.StatusCode == 401 || e.StatusCode == 403:
+		return ClassAuth
+	case e.StatusCode == 402 || e.StatusCode == 429:
+		return ClassQuota
+	case e.StatusCode == 422:
+		return ClassValidation
+	case e.StatusCode >= 500:
+		return ClassServer
+	}
+	return ClassUnknown
+}
+
+// message extracts a human-readable message from the decoded body.
+func (e *APIError) message() string {
+	switch v := e.Body.(type) {
+	case string:
+		return v
+	case map[string]any:
+		for _, k := range []string{"detail", "message", "error"} {
+			if s, ok := v[k].(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+	b, _ := json.Marshal(e.Body)
+	return string(b)
+}
+
+// NetworkError wraps a failure to reach the API or read its response.
+type NetworkError struct {
+	Endpoint string
+	Err      error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("network error on %s: %v", e.Endpoint, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error { return e.Err }
+
+// Classify returns the ErrorClass of err, looking through wrapped errors.
+func Classify(err error) ErrorClass {
+	var ae *APIError
+	if errors.As(err, &ae) {
+		return ae.Class()
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return ClassValidation
+	}
+	var re *RequestError
+	if errors.As(err, &re) {
+		return ClassValidation
+	}
+	var ne *NetworkError
+	if errors.As(err, &ne) {
+		return ClassNetwork
+	}
+	return ClassUnknown
+}
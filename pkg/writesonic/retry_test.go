@@ -0,0 +1,132 @@
+package writesonic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func fastRetries() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func TestRetriesTransientFailures(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[{"text":"ok"}]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("k", WithBaseURL(srv.URL), WithRetryPolicy(fastRetries()))
+	got, err := c.CallToAction(context.Background(), CallToActionRequest{ProductName: "Acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || len(got) != 1 || got[0].Text != "ok" {
+		t.Errorf("calls = %d, results = %+v", calls, got)
+	}
+}
+
+func TestDoesNotRetryPermanentFailures(t *testing.T) {
+	for _, status := range []int{401, 402, 422} {
+		var calls int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(status)
+			w.Write([]byte(`{"detail":[{"loc":["body","product_name"],"msg":"field required","type":"missing"}]}`))
+		}))
+		c := NewClient("k", WithBaseURL(srv.URL), WithRetryPolicy(fastRetries()))
+		_, err := c.CallToAction(context.Background(), CallToActionRequest{ProductName: "Acme"})
+		srv.Close()
+		var ae *APIError
+		if !errors.As(err, &ae) || ae.StatusCode != status {
+			t.Errorf("%d: err = %v", status, err)
+		}
+		if calls != 1 {
+			t.Errorf("%d: %d calls, want 1", status, calls)
+		}
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	c := NewClient("k", WithBaseURL(srv.URL), WithRetryPolicy(fastRetries()))
+	_, err := c.CallToAction(context.Background(), CallToActionRequest{ProductName: "Acme"})
+	if Classify(err) != ClassQuota {
+		t.Errorf("class = %v, want quota (err %v)", Classify(err), err)
+	}
+	if calls != 3 {
+		t.Errorf("%d calls, want 3", calls)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrorClass
+	}{
+		{&APIError{StatusCode: 401}, ClassAuth},
+		{&APIError{StatusCode: 403}, ClassAuth},
+		{&APIError{StatusCode: 402}, ClassQuota},
+		{&APIError{StatusCode: 429}, ClassQuota},
+		{&APIError{StatusCode: 422}, ClassValidation},
+		{&APIError{StatusCode: 500}, ClassServer},
+		{&APIError{StatusCode: 404}, ClassUnknown},
+		{&RequestError{Field: "topic", Message: "is required"}, ClassValidation},
+		{&ValidationError{}, ClassValidation},
+		{&NetworkError{Endpoint: "/pas", Err: errors.New("dial")}, ClassNetwork},
+		{fmt.Errorf("row 3: %w", &APIError{StatusCode: 503}), ClassServer},
+		{errors.New("other"), ClassUnknown},
+		{nil, ClassUnknown},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.err); got != tt.want {
+			t.Errorf("Classify(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"7", 7 * time.Second, true},
+		{"-1", 0, false},
+		{"Wed, 01 Jan 2025 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 Jan 2025 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.in, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	for n, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := p.backoff(n); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", n, got, want)
+		}
+	}
+}
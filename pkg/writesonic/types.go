@@ -1,11 +1,18 @@
-package api
+package writesonic
 
-// ContentResult is returned by most content endpoints.
-type ContentResult struct {
+import (
+	"fmt"
+	"strings"
+)
+
+// Result is a generated text, returned by every endpoint except LandingPage.
+type Result struct {
 	Text string `json:"text"`
+	// Cached is set when the result was served from the local response cache.
+	Cached bool `json:"cached,omitempty"`
 }
 
-// LandingPage is returned by the landing-pages endpoint.
+// LandingPage is the structured copy returned by Client.LandingPage.
 type LandingPage struct {
 	Title                string `json:"title"`
 	Subtitle             string `json:"subtitle"`
@@ -19,9 +26,11 @@ type LandingPage struct {
 	Feature3Subtitle     string `json:"feature_3_subtitle"`
 	CTA                  string `json:"cta"`
 	Button               string `json:"button"`
+	// Cached is set when the result was served from the local response cache.
+	Cached bool `json:"cached,omitempty"`
 }
 
-// ValidationError is returned on HTTP 422.
+// ValidationError is the decoded body of an HTTP 422 response.
 type ValidationError struct {
 	Detail []struct {
 		Loc  []interface{} `json:"loc"`
@@ -30,9 +39,22 @@ type ValidationError struct {
 	} `json:"detail"`
 }
 
+// Error renders every detail entry as "loc.path: msg", joined by "; ".
 func (v *ValidationError) Error() string {
 	if len(v.Detail) == 0 {
 		return "validation error"
 	}
-	return v.Detail[0].Msg
+	parts := make([]string, 0, len(v.Detail))
+	for _, d := range v.Detail {
+		loc := make([]string, 0, len(d.Loc))
+		for _, l := range d.Loc {
+			loc = append(loc, fmt.Sprint(l))
+		}
+		if len(loc) > 0 {
+			parts = append(parts, strings.Join(loc, ".")+": "+d.Msg)
+		} else {
+			parts = append(parts, d.Msg)
+		}
+	}
+	return strings.Join(parts, "; ")
 }
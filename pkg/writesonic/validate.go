@@ -0,0 +1,233 @@
+package writesonic
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Engines are the Writesonic quality tiers.
+var Engines = []string{"economy", "average", "good", "premium"}
+
+// Languages are the language codes accepted by the Writesonic API.
+var Languages = []string{
+	"en", "fr", "de", "es", "it", "pt-br", "pt-pt", "nl", "pl", "ru", "ja", "zh", "sv",
+	"da", "fi", "el", "hu", "ro", "cs", "sk", "sl", "bg", "lt", "lv", "et",
+}
+
+// MaxCopies is the largest num_copies the API accepts.
+const MaxCopies = 5
+
+// Field describes one JSON body field of an endpoint and its documented limits.
+type Field struct {
+	Name     string
+	Required bool
+	MinLen   int  // minimum characters, 0 for none
+	MaxLen   int  // maximum characters, 0 for none
+	List     bool // sent as a JSON array of strings; limits apply per item
+	MinItems int
+	MaxItems int
+}
+
+// Endpoints lists the request fields of every endpoint the CLI calls, keyed by
+// path. Only limits the API documents are enforced: rephrase and shorten take
+// 20-1000 characters; other fields are checked for presence only.
+var Endpoints = map[string][]Field{
+	"/blog-ideas": {
+		{Name: "topic", Required: true},
+		{Name: "primary_keyword"},
+	},
+	"/ai-article-writer-v3": {
+		{Name: "article_title", Required: true},
+		{Name: "article_intro", Required: true},
+		{Name: "article_sections", Required: true, List: true, MinItems: 1},
+	},
+	"/instant-article-writer": {
+		{Name: "article_title", Required: true},
+	},
+	"/landing-pages": {
+		{Name: "product_name", Required: true},
+		{Name: "product_description", Required: true},
+		{Name: "feature_1", Required: true},
+		{Name: "feature_2", Required: true},
+		{Name: "feature_3", Required: true},
+	},
+	"/landing-page-headlines": {
+		{Name: "product_name", Required: true},
+		{Name: "product_description", Required: true},
+	},
+	"/pas": {
+		{Name: "product_name", Required: true},
+		{Name: "product_description", Required: true},
+	},
+	"/aida": {
+		{Name: "product_name", Required: true},
+		{Name: "product_description", Required: true},
+	},
+	"/call-to-action": {
+		{Name: "product_name", Required: true},
+	},
+	"/bulletpoint-answers": {
+		{Name: "question", Required: true},
+	},
+	"/content-rephrase": {
+		{Name: "content_to_rephrase", Required: true, MinLen: 20, MaxLen: 1000},
+		{Name: "tone_of_voice"},
+	},
+	"/content-shorten": {
+		{Name: "content_to_shorten", Required: true, MinLen: 20, MaxLen: 1000},
+		{Name: "tone_of_voice"},
+	},
+	"/tone-changer": {
+		{Name: "content_to_change", Required: true},
+		{Name: "tone", Required: true},
+	},
+	"/rewrite-with-keywords": {
+		{Name: "content", Required: true},
+		{Name: "keywords", Required: true},
+	},
+	"/paragraph-writer": {
+		{Name: "topic", Required: true},
+		{Name: "instructions"},
+	},
+	"/meta-blog": {
+		{Name: "blog_title", Required: true},
+		{Name: "blog_description", Required: true},
+	},
+	"/conclusion-writer": {
+		{Name: "topic", Required: true},
+	},
+}
+
+// RequestError reports a request rejected by client-side validation before
+// it was sent. Param names a query parameter, Field a body field.
+type RequestError struct {
+	Endpoint string
+	Param    string
+	Field    string
+	Message  string
+}
+
+func (e *RequestError) Error() string {
+	name := e.Field
+	if e.Param != "" {
+		name = e.Param
+	}
+	return fmt.Sprintf("invalid request to %s: %s %s", e.Endpoint, name, e.Message)
+}
+
+// ValidateRequest checks the common query parameters and the body against
+// the endpoint's documented limits. Unknown endpoints only get the common
+// checks, so new API capabilities aren't blocked.
+func ValidateRequest(path string, params url.Values, body map[string]interface{}) error {
+	if err := validateParams(path, params); err != nil {
+		return err
+	}
+	fields, ok := Endpoints[path]
+	if !ok {
+		return nil
+	}
+	for _, f := range fields {
+		if msg := f.check(body[f.Name]); msg != "" {
+			return &RequestError{Endpoint: path, Field: f.Name, Message: msg}
+		}
+	}
+	return nil
+}
+
+func validateParams(path string, params url.Values) error {
+	fail := func(param, msg string) error {
+		return &RequestError{Endpoint: path, Param: param, Message: msg}
+	}
+	if v := params.Get("engine"); v != "" && !oneOf(Engines, v) {
+		return fail("engine", fmt.Sprintf("%q is not a known engine (use %s)", v, strings.Join(Engines, ", ")))
+	}
+	if v := params.Get("language"); v != "" && !oneOf(Languages, v) {
+		return fail("language", fmt.Sprintf("%q is not a supported language code (use one of: %s)", v, strings.Join(Languages, ", ")))
+	}
+	if v := params.Get("num_copies"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxCopies {
+			return fail("num_copies", fmt.Sprintf("must be between 1 and %d, got %s", MaxCopies, v))
+		}
+	}
+	return nil
+}
+
+// check returns a description of what is wrong with v, or "".
+func (f Field) check(v interface{}) string {
+	if v == nil {
+		if f.Required {
+			return "is required"
+		}
+		return ""
+	}
+	if f.List {
+		items, ok := stringList(v)
+		if !ok {
+			return "must be a list of strings"
+		}
+		if len(items) < f.MinItems {
+			return fmt.Sprintf("needs at least %d item(s)", f.MinItems)
+		}
+		if f.MaxItems > 0 && len(items) > f.MaxItems {
+			return fmt.Sprintf("allows at most %d items, got %d", f.MaxItems, len(items))
+		}
+		for i, item := range items {
+			if msg := f.checkLen(item); msg != "" {
+				return fmt.Sprintf("item %d %s", i+1, msg)
+			}
+		}
+		return ""
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "must be a string"
+	}
+	if f.Required && strings.TrimSpace(s) == "" {
+		return "is required"
+	}
+	return f.checkLen(s)
+}
+
+func (f Field) checkLen(s string) string {
+	n := utf8.RuneCountInString(s)
+	switch {
+	case f.MinLen > 0 && f.MaxLen > 0 && (n < f.MinLen || n > f.MaxLen):
+		return fmt.Sprintf("must be %d-%d characters, got %d", f.MinLen, f.MaxLen, n)
+	case f.MinLen > 0 && n < f.MinLen:
+		return fmt.Sprintf("must be at least %d characters, got %d", f.MinLen, n)
+	case f.MaxLen > 0 && n > f.MaxLen:
+		return fmt.Sprintf("must be at most %d characters, got %d", f.MaxLen, n)
+	}
+	return ""
+}
+
+func stringList(v interface{}) ([]string, bool) {
+	switch t := v.(type) {
+	case []string:
+		return t, true
+	case []interface{}:
+		out := make([]string, len(t))
+		for i, item := range t {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			out[i] = s
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+func oneOf(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}